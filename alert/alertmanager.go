@@ -0,0 +1,122 @@
+package alert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// notifyTimeout is the time allowed to send a notification.
+const notifyTimeout = 10 * time.Second
+
+// AlertmanagerNotifierFunc represents a configuration function for AlertmanagerNotifier.
+type AlertmanagerNotifierFunc func(n *AlertmanagerNotifier)
+
+// AlertmanagerClient configures the http client on an AlertmanagerNotifier.
+func AlertmanagerClient(client *http.Client) AlertmanagerNotifierFunc {
+	return func(n *AlertmanagerNotifier) {
+		n.client = client
+	}
+}
+
+// AlertmanagerGeneratorURL configures the link back to kage sent with the
+// alerts on an AlertmanagerNotifier.
+func AlertmanagerGeneratorURL(url string) AlertmanagerNotifierFunc {
+	return func(n *AlertmanagerNotifier) {
+		n.generatorURL = url
+	}
+}
+
+// AlertmanagerNotifier represents a Prometheus Alertmanager notifier. It
+// posts the alerts to the Alertmanager v2 API.
+type AlertmanagerNotifier struct {
+	url          string
+	generatorURL string
+
+	client *http.Client
+}
+
+// NewAlertmanagerNotifier creates and returns a new AlertmanagerNotifier.
+// The url is the address of the Alertmanager (e.g. "http://ip:9093").
+func NewAlertmanagerNotifier(url string, opts ...AlertmanagerNotifierFunc) *AlertmanagerNotifier {
+	n := &AlertmanagerNotifier{
+		url:    strings.TrimSuffix(url, "/") + "/api/v2/alerts",
+		client: &http.Client{Timeout: notifyTimeout},
+	}
+
+	for _, o := range opts {
+		o(n)
+	}
+
+	return n
+}
+
+type alertmanagerAlert struct {
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       *time.Time        `json:"endsAt,omitempty"`
+	GeneratorURL string            `json:"generatorURL,omitempty"`
+}
+
+// Notify sends the alerts to the Alertmanager.
+func (n *AlertmanagerNotifier) Notify(alerts []*Alert) error {
+	body := make([]alertmanagerAlert, 0, len(alerts))
+	for _, a := range alerts {
+		labels := map[string]string{"alertname": a.Name}
+		for key, value := range a.Labels {
+			labels[key] = value
+		}
+
+		am := alertmanagerAlert{
+			Labels:       labels,
+			Annotations:  a.Annotations,
+			StartsAt:     a.ActiveAt,
+			GeneratorURL: n.generatorURL,
+		}
+		if a.State == StateResolved {
+			endsAt := a.ResolvedAt
+			am.EndsAt = &endsAt
+		}
+
+		body = append(body, am)
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	return post(n.client, n.url, nil, data)
+}
+
+// post sends the json body to the url.
+func post(client *http.Client, url string, headers map[string]string, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,25 @@
+package alert
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlertmanagerClient(t *testing.T) {
+	client := &http.Client{}
+	n := &AlertmanagerNotifier{}
+
+	AlertmanagerClient(client)(n)
+
+	assert.Equal(t, client, n.client)
+}
+
+func TestAlertmanagerGeneratorURL(t *testing.T) {
+	n := &AlertmanagerNotifier{}
+
+	AlertmanagerGeneratorURL("http://kage/alerts")(n)
+
+	assert.Equal(t, "http://kage/alerts", n.generatorURL)
+}
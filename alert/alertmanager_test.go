@@ -0,0 +1,61 @@
+package alert_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/msales/kage/alert"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlertmanagerNotifier_Notify(t *testing.T) {
+	var body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/alerts", r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		b, _ := ioutil.ReadAll(r.Body)
+		body = string(b)
+	}))
+	defer srv.Close()
+
+	n := alert.NewAlertmanagerNotifier(srv.URL+"/", alert.AlertmanagerGeneratorURL("http://kage/alerts"))
+
+	start := time.Unix(1500000000, 0).UTC()
+	err := n.Notify([]*alert.Alert{
+		{
+			Name:        "HighLag",
+			State:       alert.StateFiring,
+			Labels:      map[string]string{"group": "foo"},
+			Annotations: map[string]string{"summary": "High lag"},
+			ActiveAt:    start,
+		},
+		{
+			Name:       "Offline",
+			State:      alert.StateResolved,
+			ActiveAt:   start,
+			ResolvedAt: start.Add(time.Minute),
+		},
+	})
+
+	want := `[{"labels":{"alertname":"HighLag","group":"foo"},"annotations":{"summary":"High lag"},"startsAt":"2017-07-14T02:40:00Z","generatorURL":"http://kage/alerts"},` +
+		`{"labels":{"alertname":"Offline"},"startsAt":"2017-07-14T02:40:00Z","endsAt":"2017-07-14T02:41:00Z","generatorURL":"http://kage/alerts"}]`
+	assert.NoError(t, err)
+	assert.Equal(t, want, body)
+}
+
+func TestAlertmanagerNotifier_NotifyError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	n := alert.NewAlertmanagerNotifier(srv.URL)
+
+	err := n.Notify([]*alert.Alert{{Name: "HighLag", State: alert.StateFiring}})
+
+	assert.Error(t, err)
+}
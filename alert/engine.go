@@ -0,0 +1,241 @@
+package alert
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ryanuber/go-glob"
+	"gopkg.in/inconshreveable/log15.v2"
+)
+
+// State represents the state of an alert.
+type State string
+
+// Alert states.
+const (
+	// StatePending is the state of an alert whose condition holds for
+	// less than the rule duration.
+	StatePending State = "pending"
+	// StateFiring is the state of an alert whose condition holds for at
+	// least the rule duration.
+	StateFiring State = "firing"
+	// StateResolved is the state of a firing alert whose condition no longer holds.
+	StateResolved State = "resolved"
+)
+
+// Alert represents an alert raised by a rule.
+type Alert struct {
+	Name        string
+	State       State
+	Labels      map[string]string
+	Annotations map[string]string
+	Value       float64
+
+	// ActiveAt is the time the condition started holding.
+	ActiveAt time.Time
+	// FiredAt is the time the alert started firing.
+	FiredAt time.Time
+	// ResolvedAt is the time the alert was resolved.
+	ResolvedAt time.Time
+}
+
+// Key returns the identity of the alert.
+func (a *Alert) Key() string {
+	return a.Name + labelsString(a.Labels)
+}
+
+func (a *Alert) copy() *Alert {
+	c := *a
+	return &c
+}
+
+// EngineFunc represents a function that configures the Engine.
+type EngineFunc func(e *Engine)
+
+// Log configures the logger on the Engine.
+func Log(log log15.Logger) EngineFunc {
+	return func(e *Engine) {
+		e.log = log
+	}
+}
+
+// Dispatchers configures the dispatchers the alerts are sent to on the Engine.
+func Dispatchers(dispatchers ...*Dispatcher) EngineFunc {
+	return func(e *Engine) {
+		e.dispatchers = append(e.dispatchers, dispatchers...)
+	}
+}
+
+// Engine represents an alerting rules engine.
+type Engine struct {
+	rules       []*Rule
+	dispatchers []*Dispatcher
+
+	alerts    map[string]*Alert
+	alertLock sync.RWMutex
+
+	log log15.Logger
+}
+
+// NewEngine creates and returns a new Engine.
+func NewEngine(rules []*Rule, opts ...EngineFunc) *Engine {
+	e := &Engine{
+		rules:  rules,
+		alerts: map[string]*Alert{},
+		log:    log15.New(),
+	}
+
+	for _, o := range opts {
+		o(e)
+	}
+
+	return e
+}
+
+// Rules returns the rules of the Engine.
+func (e *Engine) Rules() []*Rule {
+	return e.rules
+}
+
+// Evaluate evaluates the rules against the snapshot, sends the alerts to
+// the dispatchers, and returns the alerts that changed state.
+func (e *Engine) Evaluate(s *Snapshot) []*Alert {
+	changed := e.evaluate(s)
+
+	if len(e.dispatchers) > 0 {
+		active := e.Alerts()
+		for _, d := range e.dispatchers {
+			d.Dispatch(active, changed, s.Time)
+		}
+	}
+
+	return changed
+}
+
+func (e *Engine) evaluate(s *Snapshot) []*Alert {
+	e.alertLock.Lock()
+	defer e.alertLock.Unlock()
+
+	changed := []*Alert{}
+	for _, rule := range e.rules {
+		changed = append(changed, e.evaluateRule(rule, s)...)
+	}
+
+	for _, a := range changed {
+		switch a.State {
+		case StateFiring:
+			e.log.Info(fmt.Sprintf("alert: %s firing with value %v", a.Key(), a.Value))
+		case StateResolved:
+			e.log.Info(fmt.Sprintf("alert: %s resolved", a.Key()))
+		}
+	}
+
+	return changed
+}
+
+func (e *Engine) evaluateRule(rule *Rule, s *Snapshot) []*Alert {
+	changed := []*Alert{}
+	active := map[string]bool{}
+
+	for _, series := range s.series(rule.Metric) {
+		if !matches(rule.Group, series.Labels["group"]) || !matches(rule.Topic, series.Labels["topic"]) {
+			continue
+		}
+
+		if !operators[rule.Op](series.Value, rule.Threshold) {
+			continue
+		}
+
+		labels := map[string]string{}
+		for key, value := range rule.Labels {
+			labels[key] = value
+		}
+		for key, value := range series.Labels {
+			labels[key] = value
+		}
+
+		a := &Alert{
+			Name:        rule.Name,
+			State:       StatePending,
+			Labels:      labels,
+			Annotations: rule.Annotations,
+			Value:       series.Value,
+			ActiveAt:    s.Time,
+		}
+		key := a.Key()
+		active[key] = true
+
+		if existing, ok := e.alerts[key]; ok {
+			existing.Value = a.Value
+			a = existing
+		} else {
+			e.alerts[key] = a
+			if rule.For > 0 {
+				changed = append(changed, a.copy())
+			}
+		}
+
+		if a.State == StatePending && s.Time.Sub(a.ActiveAt) >= time.Duration(rule.For) {
+			a.State = StateFiring
+			a.FiredAt = s.Time
+			changed = append(changed, a.copy())
+		}
+	}
+
+	for key, a := range e.alerts {
+		if a.Name != rule.Name || active[key] {
+			continue
+		}
+
+		delete(e.alerts, key)
+		if a.State == StateFiring {
+			a.State = StateResolved
+			a.ResolvedAt = s.Time
+			changed = append(changed, a.copy())
+		}
+	}
+
+	return changed
+}
+
+// Alerts returns the pending and firing alerts, sorted by name and labels.
+func (e *Engine) Alerts() []*Alert {
+	e.alertLock.RLock()
+	defer e.alertLock.RUnlock()
+
+	alerts := make([]*Alert, 0, len(e.alerts))
+	for _, a := range e.alerts {
+		alerts = append(alerts, a.copy())
+	}
+
+	sort.Slice(alerts, func(i, j int) bool {
+		return alerts[i].Key() < alerts[j].Key()
+	})
+
+	return alerts
+}
+
+// matches checks if the value matches the pattern. An empty pattern
+// matches every value.
+func matches(pattern, value string) bool {
+	return pattern == "" || glob.Glob(pattern, value)
+}
+
+// labelsString formats the labels as "{key=value,...}", sorted by key.
+func labelsString(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, key+"="+labels[key])
+	}
+
+	return "{" + strings.Join(pairs, ",") + "}"
+}
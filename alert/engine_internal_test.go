@@ -0,0 +1,17 @@
+package alert
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/inconshreveable/log15.v2"
+)
+
+func TestLog(t *testing.T) {
+	log := log15.New()
+	e := &Engine{}
+
+	Log(log)(e)
+
+	assert.Equal(t, log, e.log)
+}
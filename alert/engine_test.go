@@ -0,0 +1,145 @@
+package alert_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/msales/kage/alert"
+	"github.com/msales/kage/store"
+	"github.com/msales/kage/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func lagSnapshot(lag int64, t time.Time) *alert.Snapshot {
+	return &alert.Snapshot{
+		ConsumerOffsets: store.ConsumerOffsets{
+			"billing": {
+				"payments": {{Offset: 100, Lag: lag / 2}, {Offset: 100, Lag: lag / 2}, nil},
+				"refunds":  {{Offset: 100, Lag: 0}},
+			},
+			"other": {
+				"payments": {{Offset: 100, Lag: lag}},
+			},
+		},
+		Time: t,
+	}
+}
+
+func TestEngine_Evaluate(t *testing.T) {
+	rules := []*alert.Rule{{
+		Name:        "HighLag",
+		Metric:      alert.MetricLag,
+		Group:       "bill*",
+		Topic:       "payments",
+		Op:          ">",
+		Threshold:   1000,
+		For:         alert.Duration(5 * time.Minute),
+		Labels:      map[string]string{"severity": "critical"},
+		Annotations: map[string]string{"summary": "High lag"},
+	}}
+	e := alert.NewEngine(rules, alert.Log(testutil.Logger))
+	start := time.Unix(1500000000, 0)
+
+	changed := e.Evaluate(lagSnapshot(2000, start))
+	assert.Len(t, changed, 1)
+	assert.Equal(t, alert.StatePending, changed[0].State)
+	assert.Equal(t, map[string]string{"severity": "critical", "group": "billing", "topic": "payments"}, changed[0].Labels)
+	assert.Equal(t, float64(2000), changed[0].Value)
+	assert.Equal(t, start, changed[0].ActiveAt)
+
+	changed = e.Evaluate(lagSnapshot(3000, start.Add(time.Minute)))
+	assert.Len(t, changed, 0)
+	assert.Len(t, e.Alerts(), 1)
+	assert.Equal(t, float64(3000), e.Alerts()[0].Value)
+
+	changed = e.Evaluate(lagSnapshot(3000, start.Add(5*time.Minute)))
+	assert.Len(t, changed, 1)
+	assert.Equal(t, alert.StateFiring, changed[0].State)
+	assert.Equal(t, start, changed[0].ActiveAt)
+	assert.Equal(t, start.Add(5*time.Minute), changed[0].FiredAt)
+
+	changed = e.Evaluate(lagSnapshot(0, start.Add(6*time.Minute)))
+	assert.Len(t, changed, 1)
+	assert.Equal(t, alert.StateResolved, changed[0].State)
+	assert.Equal(t, start.Add(6*time.Minute), changed[0].ResolvedAt)
+	assert.Len(t, e.Alerts(), 0)
+}
+
+func TestEngine_EvaluatePendingCleared(t *testing.T) {
+	rules := []*alert.Rule{{Name: "HighLag", Metric: alert.MetricLag, Op: ">", Threshold: 1000, For: alert.Duration(time.Minute)}}
+	e := alert.NewEngine(rules, alert.Log(testutil.Logger))
+	start := time.Unix(1500000000, 0)
+
+	assert.Len(t, e.Evaluate(lagSnapshot(2000, start)), 2)
+
+	changed := e.Evaluate(lagSnapshot(0, start.Add(time.Second)))
+	assert.Len(t, changed, 0)
+	assert.Len(t, e.Alerts(), 0)
+}
+
+func TestEngine_EvaluateClusterMetrics(t *testing.T) {
+	rules := []*alert.Rule{
+		{Name: "UnderReplicated", Metric: alert.MetricUnderReplicatedPartitions, Op: ">", Threshold: 0},
+		{Name: "Offline", Metric: alert.MetricOfflinePartitions, Op: ">", Threshold: 0},
+		{Name: "Disconnected", Metric: alert.MetricDisconnectedBrokers, Op: ">=", Threshold: 1},
+		{Name: "MaxLag", Metric: alert.MetricMaxLag, Op: ">", Threshold: 10},
+	}
+	e := alert.NewEngine(rules, alert.Log(testutil.Logger))
+
+	changed := e.Evaluate(&alert.Snapshot{
+		BrokerMetadata: store.BrokerMetadata{
+			"foo": {
+				{Leader: 1, Replicas: []int32{1, 2}, Isr: []int32{1}},
+				{Leader: -1, Replicas: []int32{2}, Isr: []int32{}},
+				nil,
+			},
+			"bar": {{Leader: 1, Replicas: []int32{1, 2}, Isr: []int32{1, 2}}},
+		},
+		BrokerStatuses: store.BrokerStatuses{{ID: 1, Connected: true}, {ID: 2, Connected: false}},
+		ConsumerOffsets: store.ConsumerOffsets{
+			"group": {"foo": {{Lag: 8}, {Lag: 8}}},
+		},
+		Time: time.Now(),
+	})
+
+	assert.Len(t, changed, 3)
+	alerts := e.Alerts()
+	assert.Len(t, alerts, 3)
+	assert.Equal(t, "Disconnected", alerts[0].Name)
+	assert.Equal(t, float64(1), alerts[0].Value)
+	assert.Equal(t, "Offline", alerts[1].Name)
+	assert.Equal(t, map[string]string{"topic": "foo"}, alerts[1].Labels)
+	assert.Equal(t, "UnderReplicated", alerts[2].Name)
+	assert.Equal(t, float64(2), alerts[2].Value)
+}
+
+func TestEngine_EvaluateOfflinePartitionsFromStore(t *testing.T) {
+	memStore, err := store.New()
+	assert.NoError(t, err)
+	defer memStore.Close()
+
+	memStore.SetState(&store.BrokerPartitionMetadata{Topic: "foo", Partition: 0, TopicPartitionCount: 2, Leader: 1, Replicas: []int32{1}, Isr: []int32{1}})
+	memStore.SetState(&store.BrokerPartitionMetadata{Topic: "foo", Partition: 1, TopicPartitionCount: 2, Leader: -1, Replicas: []int32{2}, Isr: []int32{}})
+
+	rules := []*alert.Rule{{Name: "Offline", Metric: alert.MetricOfflinePartitions, Op: ">", Threshold: 0}}
+	e := alert.NewEngine(rules, alert.Log(testutil.Logger))
+
+	changed := e.Evaluate(&alert.Snapshot{BrokerMetadata: memStore.BrokerMetadata(), Time: time.Now()})
+
+	assert.Len(t, changed, 1)
+	assert.Equal(t, "Offline", changed[0].Name)
+	assert.Equal(t, float64(1), changed[0].Value)
+}
+
+func TestEngine_Rules(t *testing.T) {
+	rules := []*alert.Rule{{Name: "HighLag", Metric: alert.MetricLag, Op: ">"}}
+	e := alert.NewEngine(rules)
+
+	assert.Equal(t, rules, e.Rules())
+}
+
+func TestAlert_Key(t *testing.T) {
+	a := &alert.Alert{Name: "HighLag", Labels: map[string]string{"topic": "foo", "group": "bar"}}
+
+	assert.Equal(t, "HighLag{group=bar,topic=foo}", a.Key())
+}
@@ -0,0 +1,120 @@
+package alert
+
+import (
+	"sort"
+	"time"
+
+	"gopkg.in/inconshreveable/log15.v2"
+)
+
+// Notifier represents an alert notification backend.
+type Notifier interface {
+	// Notify sends the firing and resolved alerts.
+	Notify(alerts []*Alert) error
+}
+
+// DispatcherFunc represents a function that configures the Dispatcher.
+type DispatcherFunc func(d *Dispatcher)
+
+// ResendInterval configures the interval firing alerts are sent again
+// on the Dispatcher. Firing alerts are sent once when it is 0.
+func ResendInterval(interval time.Duration) DispatcherFunc {
+	return func(d *Dispatcher) {
+		d.resend = interval
+	}
+}
+
+// DispatcherLog configures the logger on the Dispatcher.
+func DispatcherLog(log log15.Logger) DispatcherFunc {
+	return func(d *Dispatcher) {
+		d.log = log
+	}
+}
+
+// Dispatcher sends the alerts of an Engine to a Notifier. A firing alert
+// is sent when it starts firing and again every resend interval, and a
+// resolved alert is sent once if its firing alert was sent. Alerts that
+// fail to send are sent again on the next dispatch.
+type Dispatcher struct {
+	name     string
+	notifier Notifier
+	resend   time.Duration
+
+	sent     map[string]time.Time
+	resolved map[string]*Alert
+
+	log log15.Logger
+}
+
+// NewDispatcher creates and returns a new Dispatcher.
+func NewDispatcher(name string, notifier Notifier, opts ...DispatcherFunc) *Dispatcher {
+	d := &Dispatcher{
+		name:     name,
+		notifier: notifier,
+		sent:     map[string]time.Time{},
+		resolved: map[string]*Alert{},
+		log:      log15.New(),
+	}
+
+	for _, o := range opts {
+		o(d)
+	}
+
+	return d
+}
+
+// Dispatch sends the firing alerts that are due and the newly resolved alerts.
+func (d *Dispatcher) Dispatch(active, changed []*Alert, now time.Time) {
+	for _, a := range changed {
+		if a.State != StateResolved {
+			continue
+		}
+
+		if _, ok := d.sent[a.Key()]; ok {
+			d.resolved[a.Key()] = a
+		}
+	}
+
+	alerts := []*Alert{}
+	for _, a := range active {
+		if a.State != StateFiring {
+			continue
+		}
+
+		key := a.Key()
+		delete(d.resolved, key)
+		if last, ok := d.sent[key]; ok && (d.resend <= 0 || now.Sub(last) < d.resend) {
+			continue
+		}
+
+		alerts = append(alerts, a)
+	}
+	resolved := make([]*Alert, 0, len(d.resolved))
+	for _, a := range d.resolved {
+		resolved = append(resolved, a)
+	}
+	sort.Slice(resolved, func(i, j int) bool {
+		return resolved[i].Key() < resolved[j].Key()
+	})
+	alerts = append(alerts, resolved...)
+
+	if len(alerts) == 0 {
+		return
+	}
+
+	if err := d.notifier.Notify(alerts); err != nil {
+		d.log.Error("alert: " + d.name + ":" + err.Error())
+		return
+	}
+
+	for _, a := range alerts {
+		key := a.Key()
+		if a.State == StateResolved {
+			delete(d.sent, key)
+			delete(d.resolved, key)
+			continue
+		}
+
+		d.sent[key] = now
+	}
+}
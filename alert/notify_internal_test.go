@@ -0,0 +1,35 @@
+package alert
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/inconshreveable/log15.v2"
+)
+
+func TestResendInterval(t *testing.T) {
+	d := &Dispatcher{}
+
+	ResendInterval(time.Hour)(d)
+
+	assert.Equal(t, time.Hour, d.resend)
+}
+
+func TestDispatcherLog(t *testing.T) {
+	log := log15.New()
+	d := &Dispatcher{}
+
+	DispatcherLog(log)(d)
+
+	assert.Equal(t, log, d.log)
+}
+
+func TestDispatchers(t *testing.T) {
+	d := &Dispatcher{}
+	e := &Engine{}
+
+	Dispatchers(d)(e)
+
+	assert.Equal(t, []*Dispatcher{d}, e.dispatchers)
+}
@@ -0,0 +1,101 @@
+package alert_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/msales/kage/alert"
+	"github.com/msales/kage/testutil"
+	"github.com/msales/kage/testutil/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDispatcher_Dispatch(t *testing.T) {
+	start := time.Unix(1500000000, 0)
+	firing := &alert.Alert{Name: "HighLag", State: alert.StateFiring, Labels: map[string]string{"group": "foo"}}
+	pending := &alert.Alert{Name: "Offline", State: alert.StatePending}
+	resolved := &alert.Alert{Name: "HighLag", State: alert.StateResolved, Labels: map[string]string{"group": "foo"}}
+
+	n := new(mocks.MockNotifier)
+	n.On("Notify", []*alert.Alert{firing}).Return(nil).Twice()
+	n.On("Notify", []*alert.Alert{resolved}).Return(nil).Once()
+
+	d := alert.NewDispatcher("test", n, alert.ResendInterval(time.Hour), alert.DispatcherLog(testutil.Logger))
+
+	d.Dispatch([]*alert.Alert{firing, pending}, []*alert.Alert{firing}, start)
+	d.Dispatch([]*alert.Alert{firing, pending}, []*alert.Alert{}, start.Add(time.Minute))
+	d.Dispatch([]*alert.Alert{firing, pending}, []*alert.Alert{}, start.Add(time.Hour))
+	d.Dispatch([]*alert.Alert{}, []*alert.Alert{resolved}, start.Add(2*time.Hour))
+	d.Dispatch([]*alert.Alert{}, []*alert.Alert{}, start.Add(3*time.Hour))
+
+	n.AssertExpectations(t)
+}
+
+func TestDispatcher_DispatchNoResend(t *testing.T) {
+	start := time.Unix(1500000000, 0)
+	firing := &alert.Alert{Name: "HighLag", State: alert.StateFiring}
+
+	n := new(mocks.MockNotifier)
+	n.On("Notify", []*alert.Alert{firing}).Return(nil).Once()
+
+	d := alert.NewDispatcher("test", n, alert.DispatcherLog(testutil.Logger))
+
+	d.Dispatch([]*alert.Alert{firing}, []*alert.Alert{firing}, start)
+	d.Dispatch([]*alert.Alert{firing}, []*alert.Alert{}, start.Add(24*time.Hour))
+
+	n.AssertExpectations(t)
+}
+
+func TestDispatcher_DispatchUnsentResolved(t *testing.T) {
+	resolved := &alert.Alert{Name: "HighLag", State: alert.StateResolved}
+
+	n := new(mocks.MockNotifier)
+
+	d := alert.NewDispatcher("test", n, alert.DispatcherLog(testutil.Logger))
+
+	d.Dispatch([]*alert.Alert{}, []*alert.Alert{resolved}, time.Now())
+
+	n.AssertNotCalled(t, "Notify", mock.Anything)
+}
+
+func TestDispatcher_DispatchRetriesErrors(t *testing.T) {
+	start := time.Unix(1500000000, 0)
+	firing := &alert.Alert{Name: "HighLag", State: alert.StateFiring}
+	resolved := &alert.Alert{Name: "HighLag", State: alert.StateResolved}
+
+	n := new(mocks.MockNotifier)
+	n.On("Notify", []*alert.Alert{firing}).Return(errors.New("test error")).Once()
+	n.On("Notify", []*alert.Alert{firing}).Return(nil).Once()
+	n.On("Notify", []*alert.Alert{resolved}).Return(errors.New("test error")).Once()
+	n.On("Notify", []*alert.Alert{resolved}).Return(nil).Once()
+
+	d := alert.NewDispatcher("test", n, alert.DispatcherLog(testutil.Logger))
+
+	d.Dispatch([]*alert.Alert{firing}, []*alert.Alert{firing}, start)
+	d.Dispatch([]*alert.Alert{firing}, []*alert.Alert{}, start.Add(time.Minute))
+	d.Dispatch([]*alert.Alert{}, []*alert.Alert{resolved}, start.Add(2*time.Minute))
+	d.Dispatch([]*alert.Alert{}, []*alert.Alert{}, start.Add(3*time.Minute))
+	d.Dispatch([]*alert.Alert{}, []*alert.Alert{}, start.Add(4*time.Minute))
+
+	n.AssertExpectations(t)
+}
+
+func TestEngine_EvaluateDispatches(t *testing.T) {
+	n := new(mocks.MockNotifier)
+	n.On("Notify", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		alerts := args.Get(0).([]*alert.Alert)
+
+		assert.Len(t, alerts, 1)
+		assert.Equal(t, alert.StateFiring, alerts[0].State)
+	}).Once()
+
+	rules := []*alert.Rule{{Name: "HighLag", Metric: alert.MetricLag, Group: "other", Op: ">", Threshold: 1000}}
+	d := alert.NewDispatcher("test", n, alert.DispatcherLog(testutil.Logger))
+	e := alert.NewEngine(rules, alert.Dispatchers(d), alert.Log(testutil.Logger))
+
+	e.Evaluate(lagSnapshot(2000, time.Now()))
+
+	n.AssertExpectations(t)
+}
@@ -0,0 +1,169 @@
+package alert
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// Rule metrics.
+const (
+	// MetricLag is the total lag of a consumer group topic.
+	MetricLag = "lag"
+	// MetricMaxLag is the largest partition lag of a consumer group topic.
+	MetricMaxLag = "max_lag"
+	// MetricOfflinePartitions is the number of partitions of a topic without a leader.
+	MetricOfflinePartitions = "offline_partitions"
+	// MetricUnderReplicatedPartitions is the number of partitions of a topic
+	// with replicas out of sync.
+	MetricUnderReplicatedPartitions = "under_replicated_partitions"
+	// MetricDisconnectedBrokers is the number of brokers kage is not connected to.
+	MetricDisconnectedBrokers = "disconnected_brokers"
+)
+
+// metricLabels maps the rule metrics to the labels of their series.
+var metricLabels = map[string][]string{
+	MetricLag:                       {"group", "topic"},
+	MetricMaxLag:                    {"group", "topic"},
+	MetricOfflinePartitions:         {"topic"},
+	MetricUnderReplicatedPartitions: {"topic"},
+	MetricDisconnectedBrokers:       {},
+}
+
+// operators maps the rule operators to their comparison.
+var operators = map[string]func(a, b float64) bool{
+	">":  func(a, b float64) bool { return a > b },
+	">=": func(a, b float64) bool { return a >= b },
+	"<":  func(a, b float64) bool { return a < b },
+	"<=": func(a, b float64) bool { return a <= b },
+	"==": func(a, b float64) bool { return a == b },
+	"!=": func(a, b float64) bool { return a != b },
+}
+
+// Duration represents a duration decoded from a string such as "5m".
+type Duration time.Duration
+
+// UnmarshalJSON decodes the duration from a JSON string.
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(v)
+	return nil
+}
+
+// MarshalJSON encodes the duration as a JSON string.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// Rule represents an alerting rule.
+type Rule struct {
+	// Name is the name of the alerts raised by the rule.
+	Name string `json:"name"`
+
+	// Metric is the metric the rule is evaluated against.
+	Metric string `json:"metric"`
+
+	// Group is the consumer group pattern. This may contain wildcards.
+	Group string `json:"group"`
+
+	// Topic is the topic pattern. This may contain wildcards.
+	Topic string `json:"topic"`
+
+	// Op is the operator comparing the metric to the threshold.
+	Op string `json:"op"`
+
+	// Threshold is the value the metric is compared to.
+	Threshold float64 `json:"threshold"`
+
+	// For is how long the condition must hold before the alert fires.
+	For Duration `json:"for"`
+
+	// Labels are the additional labels of the alerts.
+	Labels map[string]string `json:"labels"`
+
+	// Annotations are the descriptions of the alerts.
+	Annotations map[string]string `json:"annotations"`
+}
+
+// Validate checks the rule is complete and known.
+func (r *Rule) Validate() error {
+	if r.Name == "" {
+		return errors.New("alert: rule name is required")
+	}
+
+	labels, ok := metricLabels[r.Metric]
+	if !ok {
+		return fmt.Errorf("alert: rule %s: unknown metric \"%s\"", r.Name, r.Metric)
+	}
+
+	if _, ok := operators[r.Op]; !ok {
+		return fmt.Errorf("alert: rule %s: unknown operator \"%s\"", r.Name, r.Op)
+	}
+
+	if r.Group != "" && !contains(labels, "group") {
+		return fmt.Errorf("alert: rule %s: metric \"%s\" has no group", r.Name, r.Metric)
+	}
+
+	if r.Topic != "" && !contains(labels, "topic") {
+		return fmt.Errorf("alert: rule %s: metric \"%s\" has no topic", r.Name, r.Metric)
+	}
+
+	return nil
+}
+
+// ParseRules decodes and validates the rules of a JSON config.
+func ParseRules(r io.Reader) ([]*Rule, error) {
+	var config struct {
+		Rules []*Rule `json:"rules"`
+	}
+	if err := json.NewDecoder(r).Decode(&config); err != nil {
+		return nil, fmt.Errorf("alert: invalid config: %s", err)
+	}
+
+	names := map[string]bool{}
+	for _, rule := range config.Rules {
+		if err := rule.Validate(); err != nil {
+			return nil, err
+		}
+
+		if names[rule.Name] {
+			return nil, fmt.Errorf("alert: duplicate rule %s", rule.Name)
+		}
+		names[rule.Name] = true
+	}
+
+	return config.Rules, nil
+}
+
+// LoadRules reads the rules from a JSON config file.
+func LoadRules(path string) ([]*Rule, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ParseRules(f)
+}
+
+func contains(s []string, v string) bool {
+	for _, item := range s {
+		if item == v {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,85 @@
+package alert_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/msales/kage/alert"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRules(t *testing.T) {
+	config := `{
+		"rules": [
+			{"name": "HighLag", "metric": "lag", "group": "billing", "topic": "payments", "op": ">", "threshold": 10000, "for": "5m", "labels": {"severity": "critical"}},
+			{"name": "UnderReplicated", "metric": "under_replicated_partitions", "op": ">", "threshold": 0}
+		]
+	}`
+
+	rules, err := alert.ParseRules(strings.NewReader(config))
+
+	assert.NoError(t, err)
+	assert.Len(t, rules, 2)
+	assert.Equal(t, "HighLag", rules[0].Name)
+	assert.Equal(t, alert.MetricLag, rules[0].Metric)
+	assert.Equal(t, "billing", rules[0].Group)
+	assert.Equal(t, "payments", rules[0].Topic)
+	assert.Equal(t, ">", rules[0].Op)
+	assert.Equal(t, float64(10000), rules[0].Threshold)
+	assert.Equal(t, alert.Duration(5*time.Minute), rules[0].For)
+	assert.Equal(t, map[string]string{"severity": "critical"}, rules[0].Labels)
+	assert.Equal(t, alert.Duration(0), rules[1].For)
+}
+
+func TestParseRules_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+	}{
+		{"json", `{"rules": [`},
+		{"duration", `{"rules": [{"name": "a", "metric": "lag", "op": ">", "for": "five"}]}`},
+		{"name", `{"rules": [{"metric": "lag", "op": ">"}]}`},
+		{"metric", `{"rules": [{"name": "a", "metric": "foo", "op": ">"}]}`},
+		{"operator", `{"rules": [{"name": "a", "metric": "lag", "op": "=>"}]}`},
+		{"group", `{"rules": [{"name": "a", "metric": "offline_partitions", "group": "foo", "op": ">"}]}`},
+		{"topic", `{"rules": [{"name": "a", "metric": "disconnected_brokers", "topic": "foo", "op": ">"}]}`},
+		{"duplicate", `{"rules": [{"name": "a", "metric": "lag", "op": ">"}, {"name": "a", "metric": "lag", "op": "<"}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := alert.ParseRules(strings.NewReader(tt.config))
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoadRules(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kage")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "rules.json")
+	config := `{"rules": [{"name": "Offline", "metric": "offline_partitions", "op": ">", "threshold": 0}]}`
+	if err := ioutil.WriteFile(path, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	rules, err := alert.LoadRules(path)
+
+	assert.NoError(t, err)
+	assert.Len(t, rules, 1)
+}
+
+func TestLoadRules_MissingFile(t *testing.T) {
+	_, err := alert.LoadRules("/does/not/exist.json")
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,115 @@
+package alert
+
+import (
+	"time"
+
+	"github.com/msales/kage/store"
+)
+
+// Snapshot represents the state of the cluster the rules are evaluated against.
+type Snapshot struct {
+	BrokerMetadata  store.BrokerMetadata
+	BrokerStatuses  store.BrokerStatuses
+	ConsumerOffsets store.ConsumerOffsets
+
+	// Time is the time the snapshot was taken.
+	Time time.Time
+}
+
+// series represents the value of a metric for a set of labels.
+type series struct {
+	Labels map[string]string
+	Value  float64
+}
+
+// series returns the series of the metric in the snapshot.
+func (s *Snapshot) series(metric string) []series {
+	switch metric {
+	case MetricLag, MetricMaxLag:
+		return s.lagSeries(metric)
+
+	case MetricOfflinePartitions, MetricUnderReplicatedPartitions:
+		return s.partitionSeries(metric)
+
+	case MetricDisconnectedBrokers:
+		if s.BrokerStatuses == nil {
+			return nil
+		}
+
+		disconnected := 0
+		for _, status := range s.BrokerStatuses {
+			if !status.Connected {
+				disconnected++
+			}
+		}
+		return []series{{Labels: map[string]string{}, Value: float64(disconnected)}}
+	}
+
+	return nil
+}
+
+func (s *Snapshot) lagSeries(metric string) []series {
+	result := []series{}
+	for group, topics := range s.ConsumerOffsets {
+		for topic, partitions := range topics {
+			var lag, maxLag int64
+			found := false
+			for _, offset := range partitions {
+				if offset == nil {
+					continue
+				}
+
+				found = true
+				lag += offset.Lag
+				if offset.Lag > maxLag {
+					maxLag = offset.Lag
+				}
+			}
+
+			if !found {
+				continue
+			}
+
+			value := lag
+			if metric == MetricMaxLag {
+				value = maxLag
+			}
+			result = append(result, series{
+				Labels: map[string]string{"group": group, "topic": topic},
+				Value:  float64(value),
+			})
+		}
+	}
+
+	return result
+}
+
+func (s *Snapshot) partitionSeries(metric string) []series {
+	result := []series{}
+	for topic, partitions := range s.BrokerMetadata {
+		count := 0
+		for _, metadata := range partitions {
+			if metadata == nil {
+				continue
+			}
+
+			switch metric {
+			case MetricOfflinePartitions:
+				if metadata.Leader < 0 {
+					count++
+				}
+			case MetricUnderReplicatedPartitions:
+				if len(metadata.Isr) < len(metadata.Replicas) {
+					count++
+				}
+			}
+		}
+
+		result = append(result, series{
+			Labels: map[string]string{"topic": topic},
+			Value:  float64(count),
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,127 @@
+package alert
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"text/template"
+
+	"github.com/msales/kage/utils"
+)
+
+// DefaultWebhookTemplate is the default webhook body template. It sends
+// the message as JSON.
+const DefaultWebhookTemplate = "{{json .}}"
+
+// ParseWebhookTemplate parses a webhook body template. The template is
+// executed with a WebhookMessage, and can use the "json" function to
+// encode a value as JSON and the "labels" function to format labels.
+func ParseWebhookTemplate(text string) (*template.Template, error) {
+	return template.New("webhook").Funcs(template.FuncMap{
+		"json": func(v interface{}) (string, error) {
+			b, err := json.Marshal(v)
+			return string(b), err
+		},
+		"labels": labelsString,
+	}).Parse(text)
+}
+
+// WebhookMessage represents the data a webhook body template is executed with.
+type WebhookMessage struct {
+	// Status is "firing" when any of the alerts is firing, otherwise "resolved".
+	Status string         `json:"status"`
+	Alerts []WebhookAlert `json:"alerts"`
+}
+
+// WebhookAlert represents an alert of a WebhookMessage.
+type WebhookAlert struct {
+	Name        string            `json:"name"`
+	State       string            `json:"state"`
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+	Value       float64           `json:"value"`
+
+	// ActiveAt is Unix time in milliseconds.
+	ActiveAt int64 `json:"active_at"`
+	// ResolvedAt is Unix time in milliseconds, or 0 when not resolved.
+	ResolvedAt int64 `json:"resolved_at,omitempty"`
+}
+
+// WebhookNotifierFunc represents a configuration function for WebhookNotifier.
+type WebhookNotifierFunc func(n *WebhookNotifier)
+
+// WebhookTemplate configures the body template on a WebhookNotifier.
+func WebhookTemplate(tmpl *template.Template) WebhookNotifierFunc {
+	return func(n *WebhookNotifier) {
+		n.tmpl = tmpl
+	}
+}
+
+// WebhookHeaders configures the additional request headers on a WebhookNotifier.
+func WebhookHeaders(headers map[string]string) WebhookNotifierFunc {
+	return func(n *WebhookNotifier) {
+		n.headers = headers
+	}
+}
+
+// WebhookClient configures the http client on a WebhookNotifier.
+func WebhookClient(client *http.Client) WebhookNotifierFunc {
+	return func(n *WebhookNotifier) {
+		n.client = client
+	}
+}
+
+// WebhookNotifier represents a generic JSON webhook notifier. It posts the
+// alerts in a body rendered from a template, so it can be adapted to
+// services such as Slack incoming webhooks.
+type WebhookNotifier struct {
+	url     string
+	headers map[string]string
+	tmpl    *template.Template
+
+	client *http.Client
+}
+
+// NewWebhookNotifier creates and returns a new WebhookNotifier.
+func NewWebhookNotifier(url string, opts ...WebhookNotifierFunc) *WebhookNotifier {
+	tmpl, _ := ParseWebhookTemplate(DefaultWebhookTemplate)
+
+	n := &WebhookNotifier{
+		url:    url,
+		tmpl:   tmpl,
+		client: &http.Client{Timeout: notifyTimeout},
+	}
+
+	for _, o := range opts {
+		o(n)
+	}
+
+	return n
+}
+
+// Notify sends the alerts to the webhook.
+func (n *WebhookNotifier) Notify(alerts []*Alert) error {
+	msg := WebhookMessage{Status: string(StateResolved), Alerts: make([]WebhookAlert, 0, len(alerts))}
+	for _, a := range alerts {
+		if a.State == StateFiring {
+			msg.Status = string(StateFiring)
+		}
+
+		msg.Alerts = append(msg.Alerts, WebhookAlert{
+			Name:        a.Name,
+			State:       string(a.State),
+			Labels:      a.Labels,
+			Annotations: a.Annotations,
+			Value:       a.Value,
+			ActiveAt:    utils.Millis(a.ActiveAt),
+			ResolvedAt:  utils.Millis(a.ResolvedAt),
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := n.tmpl.Execute(&buf, msg); err != nil {
+		return err
+	}
+
+	return post(n.client, n.url, n.headers, buf.Bytes())
+}
@@ -0,0 +1,34 @@
+package alert
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookTemplate(t *testing.T) {
+	tmpl, _ := ParseWebhookTemplate("{}")
+	n := &WebhookNotifier{}
+
+	WebhookTemplate(tmpl)(n)
+
+	assert.Equal(t, tmpl, n.tmpl)
+}
+
+func TestWebhookHeaders(t *testing.T) {
+	n := &WebhookNotifier{}
+
+	WebhookHeaders(map[string]string{"foo": "bar"})(n)
+
+	assert.Equal(t, "bar", n.headers["foo"])
+}
+
+func TestWebhookClient(t *testing.T) {
+	client := &http.Client{}
+	n := &WebhookNotifier{}
+
+	WebhookClient(client)(n)
+
+	assert.Equal(t, client, n.client)
+}
@@ -0,0 +1,80 @@
+package alert_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/msales/kage/alert"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookNotifier_Notify(t *testing.T) {
+	var body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "secret", r.Header.Get("X-Token"))
+
+		b, _ := ioutil.ReadAll(r.Body)
+		body = string(b)
+	}))
+	defer srv.Close()
+
+	n := alert.NewWebhookNotifier(srv.URL, alert.WebhookHeaders(map[string]string{"X-Token": "secret"}))
+
+	err := n.Notify([]*alert.Alert{
+		{
+			Name:     "HighLag",
+			State:    alert.StateFiring,
+			Labels:   map[string]string{"group": "foo"},
+			Value:    2000,
+			ActiveAt: time.Unix(1500000000, 0),
+		},
+	})
+
+	want := `{"status":"firing","alerts":[{"name":"HighLag","state":"firing","labels":{"group":"foo"},"annotations":null,"value":2000,"active_at":1500000000000}]}`
+	assert.NoError(t, err)
+	assert.Equal(t, want, body)
+}
+
+func TestWebhookNotifier_NotifyTemplate(t *testing.T) {
+	var body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		body = string(b)
+	}))
+	defer srv.Close()
+
+	tmpl, err := alert.ParseWebhookTemplate(`{"text": {{json (printf "%s: %s" .Status (index .Alerts 0).Name)}}, "labels": "{{labels (index .Alerts 0).Labels}}"}`)
+	assert.NoError(t, err)
+
+	n := alert.NewWebhookNotifier(srv.URL, alert.WebhookTemplate(tmpl))
+
+	err = n.Notify([]*alert.Alert{
+		{Name: "High \"Lag\"", State: alert.StateResolved, Labels: map[string]string{"group": "foo", "topic": "bar"}},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, `{"text": "resolved: High \"Lag\"", "labels": "{group=foo,topic=bar}"}`, body)
+}
+
+func TestWebhookNotifier_NotifyError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	n := alert.NewWebhookNotifier(srv.URL)
+
+	err := n.Notify([]*alert.Alert{{Name: "HighLag", State: alert.StateFiring}})
+
+	assert.Error(t, err)
+}
+
+func TestParseWebhookTemplate_Invalid(t *testing.T) {
+	_, err := alert.ParseWebhookTemplate("{{.Status")
+
+	assert.Error(t, err)
+}
@@ -1,6 +1,14 @@
 package kage
 
 import (
+	"sort"
+	"time"
+
+	"github.com/msales/kage/alert"
+	"github.com/msales/kage/kafka"
+	"github.com/msales/kage/metrics"
+	"github.com/msales/kage/store"
+	"github.com/msales/kage/utils"
 	"gopkg.in/inconshreveable/log15.v2"
 )
 
@@ -9,8 +17,12 @@ type Application struct {
 	Store     Store
 	Reporters *Reporters
 	Monitor   Monitor
+	Metrics   *metrics.Registry
+	Alerts    *alert.Engine
 
 	Logger log15.Logger
+
+	regressionID int64
 }
 
 // NewApplication creates an instance of Application.
@@ -42,8 +54,81 @@ func (a *Application) Report() {
 	bm := a.Store.BrokerMetadata()
 	a.Reporters.ReportBrokerMetadata(&bm)
 
+	var bs store.BrokerStatuses
+	if a.Monitor != nil {
+		bs = brokerStatuses(a.Monitor.Brokers(), bm)
+		a.Reporters.ReportBrokerStatus(&bs)
+	}
+
 	co := a.Store.ConsumerOffsets()
 	a.Reporters.ReportConsumerOffsets(&co)
+
+	if a.Alerts != nil {
+		a.Alerts.Evaluate(&alert.Snapshot{
+			BrokerMetadata:  bm,
+			BrokerStatuses:  bs,
+			ConsumerOffsets: co,
+			Time:            time.Now(),
+		})
+	}
+
+	or := store.OffsetRegressions{}
+	for _, r := range a.Store.OffsetRegressions() {
+		if r.ID <= a.regressionID {
+			continue
+		}
+
+		or = append(or, r)
+		a.regressionID = r.ID
+	}
+	if len(or) > 0 {
+		a.Reporters.ReportOffsetRegressions(&or)
+	}
+
+	if a.Metrics != nil {
+		m := a.Metrics.Snapshot()
+		a.Reporters.ReportMetrics(&m)
+	}
+}
+
+// brokerStatuses returns the status of the brokers, counting the partitions
+// each broker leads and the replicas it hosts from the broker metadata.
+func brokerStatuses(brokers []kafka.Broker, bm store.BrokerMetadata) store.BrokerStatuses {
+	leaders := map[int32]int{}
+	replicas := map[int32]int{}
+	for _, partitions := range bm {
+		for _, metadata := range partitions {
+			if metadata == nil {
+				continue
+			}
+
+			if metadata.Leader >= 0 {
+				leaders[metadata.Leader]++
+			}
+			for _, id := range metadata.Replicas {
+				replicas[id]++
+			}
+		}
+	}
+
+	ts := utils.Millis(time.Now())
+	statuses := make(store.BrokerStatuses, 0, len(brokers))
+	for _, b := range brokers {
+		statuses = append(statuses, &store.BrokerStatus{
+			ID:         b.ID,
+			Connected:  b.Connected,
+			Controller: b.Controller,
+			Leaders:    leaders[b.ID],
+			Replicas:   replicas[b.ID],
+			Timestamp:  ts,
+		})
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].ID < statuses[j].ID
+	})
+
+	return statuses
 }
 
 // IsHealthy checks the health of the Application.
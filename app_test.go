@@ -4,9 +4,14 @@ import (
 	"testing"
 
 	"github.com/msales/kage"
+	"github.com/msales/kage/alert"
+	"github.com/msales/kage/kafka"
+	"github.com/msales/kage/metrics"
 	"github.com/msales/kage/store"
+	"github.com/msales/kage/testutil"
 	"github.com/msales/kage/testutil/mocks"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestNewApplication(t *testing.T) {
@@ -61,18 +66,20 @@ func TestApplication_Report(t *testing.T) {
 	bo := store.BrokerOffsets{}
 	bm := store.BrokerMetadata{}
 	co := store.ConsumerOffsets{}
+	or := store.OffsetRegressions{}
 
 	store := new(mocks.MockStore)
 	store.On("BrokerOffsets").Return(bo)
 	store.On("BrokerMetadata").Return(bm)
 	store.On("ConsumerOffsets").Return(co)
+	store.On("OffsetRegressions").Return(or)
 
 	reporters := &kage.Reporters{}
 
 	reporter := new(mocks.MockReporter)
-	reporter.On("ReportBrokerOffsets", &bo).Return()
-	reporter.On("ReportBrokerMetadata", &bm).Return()
-	reporter.On("ReportConsumerOffsets", &co).Return()
+	reporter.On("ReportBrokerOffsets", &bo).Return(nil)
+	reporter.On("ReportBrokerMetadata", &bm).Return(nil)
+	reporter.On("ReportConsumerOffsets", &co).Return(nil)
 	reporters.Add("test", reporter)
 
 	app := &kage.Application{
@@ -86,6 +93,125 @@ func TestApplication_Report(t *testing.T) {
 	reporter.AssertExpectations(t)
 }
 
+func TestApplication_ReportBrokerStatus(t *testing.T) {
+	bo := store.BrokerOffsets{}
+	bm := store.BrokerMetadata{
+		"foo": []*store.Metadata{
+			{Leader: 1, Replicas: []int32{1, 2}},
+			{Leader: 2, Replicas: []int32{2, 1}},
+			{Leader: -1, Replicas: []int32{2}},
+		},
+	}
+	co := store.ConsumerOffsets{}
+	or := store.OffsetRegressions{}
+
+	memStore := new(mocks.MockStore)
+	memStore.On("BrokerOffsets").Return(bo)
+	memStore.On("BrokerMetadata").Return(bm)
+	memStore.On("ConsumerOffsets").Return(co)
+	memStore.On("OffsetRegressions").Return(or)
+
+	monitor := new(mocks.MockMonitor)
+	monitor.On("Brokers").Return([]kafka.Broker{{ID: 2, Connected: false}, {ID: 1, Connected: true, Controller: true}})
+
+	reporters := &kage.Reporters{}
+
+	reporter := new(mocks.MockReporter)
+	reporter.On("ReportBrokerOffsets", mock.Anything).Return(nil)
+	reporter.On("ReportBrokerMetadata", mock.Anything).Return(nil)
+	reporter.On("ReportConsumerOffsets", mock.Anything).Return(nil)
+	reporter.On("ReportBrokerStatus", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		statuses := *args.Get(0).(*store.BrokerStatuses)
+
+		assert.Len(t, statuses, 2)
+		assert.Equal(t, int32(1), statuses[0].ID)
+		assert.True(t, statuses[0].Connected)
+		assert.True(t, statuses[0].Controller)
+		assert.Equal(t, 1, statuses[0].Leaders)
+		assert.Equal(t, 2, statuses[0].Replicas)
+		assert.Equal(t, int32(2), statuses[1].ID)
+		assert.False(t, statuses[1].Connected)
+		assert.Equal(t, 1, statuses[1].Leaders)
+		assert.Equal(t, 3, statuses[1].Replicas)
+		assert.NotZero(t, statuses[1].Timestamp)
+	})
+	reporters.Add("test", reporter)
+
+	app := &kage.Application{
+		Store:     memStore,
+		Reporters: reporters,
+		Monitor:   monitor,
+	}
+
+	app.Report()
+
+	reporter.AssertExpectations(t)
+}
+
+func TestApplication_ReportOffsetRegressions(t *testing.T) {
+	bo := store.BrokerOffsets{}
+	bm := store.BrokerMetadata{}
+	co := store.ConsumerOffsets{}
+	or := store.OffsetRegressions{{ID: 1}, {ID: 2}}
+
+	store := new(mocks.MockStore)
+	store.On("BrokerOffsets").Return(bo)
+	store.On("BrokerMetadata").Return(bm)
+	store.On("ConsumerOffsets").Return(co)
+	store.On("OffsetRegressions").Return(or)
+
+	reporters := &kage.Reporters{}
+
+	reporter := new(mocks.MockReporter)
+	reporter.On("ReportBrokerOffsets", mock.Anything).Return(nil)
+	reporter.On("ReportBrokerMetadata", mock.Anything).Return(nil)
+	reporter.On("ReportConsumerOffsets", mock.Anything).Return(nil)
+	reporter.On("ReportOffsetRegressions", &or).Return(nil).Once()
+	reporters.Add("test", reporter)
+
+	app := &kage.Application{
+		Store:     store,
+		Reporters: reporters,
+	}
+
+	app.Report()
+	app.Report()
+
+	reporter.AssertExpectations(t)
+}
+
+func TestApplication_ReportEvaluatesAlerts(t *testing.T) {
+	bo := store.BrokerOffsets{}
+	bm := store.BrokerMetadata{}
+	co := store.ConsumerOffsets{
+		"foo": {"test": {{Offset: 100, Lag: 2000}}},
+	}
+	or := store.OffsetRegressions{}
+
+	memStore := new(mocks.MockStore)
+	memStore.On("BrokerOffsets").Return(bo)
+	memStore.On("BrokerMetadata").Return(bm)
+	memStore.On("ConsumerOffsets").Return(co)
+	memStore.On("OffsetRegressions").Return(or)
+
+	reporters := &kage.Reporters{}
+
+	rules := []*alert.Rule{{Name: "HighLag", Metric: alert.MetricLag, Op: ">", Threshold: 1000}}
+	engine := alert.NewEngine(rules, alert.Log(testutil.Logger))
+
+	app := &kage.Application{
+		Store:     memStore,
+		Reporters: reporters,
+		Alerts:    engine,
+	}
+
+	app.Report()
+
+	alerts := engine.Alerts()
+	assert.Len(t, alerts, 1)
+	assert.Equal(t, alert.StateFiring, alerts[0].State)
+}
+
 func TestApplication_Collect(t *testing.T) {
 	monitor := new(mocks.MockMonitor)
 	monitor.On("Collect").Once()
@@ -98,3 +224,43 @@ func TestApplication_Collect(t *testing.T) {
 
 	monitor.AssertExpectations(t)
 }
+
+func TestApplication_ReportMetrics(t *testing.T) {
+	bo := store.BrokerOffsets{}
+	bm := store.BrokerMetadata{}
+	co := store.ConsumerOffsets{}
+	or := store.OffsetRegressions{}
+
+	store := new(mocks.MockStore)
+	store.On("BrokerOffsets").Return(bo)
+	store.On("BrokerMetadata").Return(bm)
+	store.On("ConsumerOffsets").Return(co)
+	store.On("OffsetRegressions").Return(or)
+
+	registry := metrics.NewRegistry()
+	registry.Counter("test").Inc(1)
+
+	reporters := &kage.Reporters{}
+
+	reporter := new(mocks.MockReporter)
+	reporter.On("ReportBrokerOffsets", mock.Anything).Return(nil)
+	reporter.On("ReportBrokerMetadata", mock.Anything).Return(nil)
+	reporter.On("ReportConsumerOffsets", mock.Anything).Return(nil)
+	reporter.On("ReportMetrics", &metrics.Snapshot{{
+		Name:   "test",
+		Type:   metrics.TypeCounter,
+		Tags:   map[string]string{},
+		Values: map[string]float64{"count": 1},
+	}}).Return(nil)
+	reporters.Add("test", reporter)
+
+	app := &kage.Application{
+		Store:     store,
+		Reporters: reporters,
+		Metrics:   registry,
+	}
+
+	app.Report()
+
+	reporter.AssertExpectations(t)
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/msales/kage"
+	"github.com/msales/kage/metrics"
+	"github.com/ryanuber/go-glob"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// Nagios plugin states, in order of severity.
+const (
+	checkOK       = 0
+	checkWarning  = 1
+	checkCritical = 2
+	checkUnknown  = 3
+)
+
+// checkStates maps the plugin states to their name.
+var checkStates = map[int]string{
+	checkOK:       "OK",
+	checkWarning:  "WARNING",
+	checkCritical: "CRITICAL",
+	checkUnknown:  "UNKNOWN",
+}
+
+// checkThreshold represents the warning and critical thresholds of a value.
+// A value over a threshold raises its state, and negative thresholds are disabled.
+type checkThreshold struct {
+	Warning  int64
+	Critical int64
+}
+
+// State returns the state of the value.
+func (t checkThreshold) State(v int64) int {
+	switch {
+	case t.Critical >= 0 && v > t.Critical:
+		return checkCritical
+	case t.Warning >= 0 && v > t.Warning:
+		return checkWarning
+	default:
+		return checkOK
+	}
+}
+
+// Perfdata formats the value and thresholds as Nagios performance data.
+func (t checkThreshold) Perfdata(label string, v int64, max string) string {
+	perfdata := fmt.Sprintf("%s=%d;%s;%s;0;%s", label, v, formatThreshold(t.Warning), formatThreshold(t.Critical), max)
+
+	return strings.TrimSuffix(perfdata, ";")
+}
+
+func formatThreshold(v int64) string {
+	if v < 0 {
+		return ""
+	}
+
+	return fmt.Sprint(v)
+}
+
+// checkResult represents the outcome of a check.
+type checkResult struct {
+	State    int
+	Problems []string
+	Summary  []string
+	Errors   []string
+	Perfdata []string
+}
+
+// Add adds a checked value to the result.
+func (r *checkResult) Add(state int, summary string, perfdata string) {
+	if state > r.State {
+		r.State = state
+	}
+	if state != checkOK {
+		r.Problems = append(r.Problems, summary)
+	}
+
+	r.Summary = append(r.Summary, summary)
+	r.Perfdata = append(r.Perfdata, perfdata)
+}
+
+// String formats the result as a Nagios status line. Only the values
+// that raised the state are listed when it is not OK, followed by the errors.
+func (r *checkResult) String() string {
+	text := r.Summary
+	if r.State != checkOK {
+		text = r.Problems
+	}
+	text = append(append([]string{}, text...), r.Errors...)
+
+	status := fmt.Sprintf("KAGE %s - %s", checkStates[r.State], strings.Join(text, ", "))
+	if len(r.Perfdata) == 0 {
+		return status
+	}
+
+	return status + " | " + strings.Join(r.Perfdata, " ")
+}
+
+func runCheck(c *cli.Context) {
+	app, err := collectOnce(c)
+	if err != nil {
+		fmt.Printf("KAGE %s - %s\n", checkStates[checkUnknown], err)
+		os.Exit(checkUnknown)
+	}
+
+	result := check(app, c.String(FlagLagGroup),
+		checkThreshold{Warning: c.Int64(FlagCheckLagWarning), Critical: c.Int64(FlagCheckLagCritical)},
+		checkThreshold{Warning: c.Int64(FlagCheckOfflineWarning), Critical: c.Int64(FlagCheckOfflineCritical)},
+		checkThreshold{Warning: c.Int64(FlagCheckBrokersWarning), Critical: c.Int64(FlagCheckBrokersCritical)},
+	)
+
+	fmt.Println(result)
+	app.Close()
+	os.Exit(result.State)
+}
+
+// check checks the largest group lag of the groups matching the pattern, the
+// offline partitions and the disconnected brokers against their thresholds.
+// The result is unknown when there are no brokers or no metadata to check,
+// and the collection errors are listed with the result otherwise.
+func check(app *kage.Application, group string, lag, offline, brokers checkThreshold) *checkResult {
+	result := &checkResult{}
+
+	if phases := collectErrors(app.Metrics); len(phases) > 0 {
+		result.Errors = append(result.Errors, "cannot collect "+strings.Join(phases, ", "))
+	}
+
+	all := app.Monitor.Brokers()
+	metadata := app.Store.BrokerMetadata()
+	if len(all) == 0 || len(metadata) == 0 {
+		result.State = checkUnknown
+		result.Problems = []string{"no brokers or metadata to check"}
+		return result
+	}
+
+	// The total lag of every group, over all its topics
+	groupLags := map[string]int64{}
+	for g, topics := range app.Store.ConsumerOffsets() {
+		if group != "" && !glob.Glob(group, g) {
+			continue
+		}
+
+		for _, partitions := range topics {
+			for _, offset := range partitions {
+				if offset != nil {
+					groupLags[g] += offset.Lag
+				}
+			}
+		}
+	}
+
+	groups := make([]string, 0, len(groupLags))
+	for g := range groupLags {
+		groups = append(groups, g)
+	}
+	sort.Strings(groups)
+
+	var maxLag int64
+	maxGroup := ""
+	for _, g := range groups {
+		if maxGroup == "" || groupLags[g] > maxLag {
+			maxLag = groupLags[g]
+			maxGroup = g
+		}
+	}
+
+	lagSummary := "no consumer groups"
+	if maxGroup != "" {
+		lagSummary = fmt.Sprintf("group %s lag %d", maxGroup, maxLag)
+	}
+	result.Add(lag.State(maxLag), lagSummary, lag.Perfdata("max_lag", maxLag, ""))
+
+	// Partitions without a leader have no broker to read them from, as
+	// have partitions missing from the metadata.
+	var offlineCount int64
+	for _, partitions := range metadata {
+		for _, m := range partitions {
+			if m == nil || m.Leader < 0 {
+				offlineCount++
+			}
+		}
+	}
+	result.Add(offline.State(offlineCount), fmt.Sprintf("%d offline partitions", offlineCount), offline.Perfdata("offline_partitions", offlineCount, ""))
+
+	var disconnected int64
+	for _, b := range all {
+		if !b.Connected {
+			disconnected++
+		}
+	}
+	result.Add(
+		brokers.State(disconnected),
+		fmt.Sprintf("%d of %d brokers disconnected", disconnected, len(all)),
+		brokers.Perfdata("disconnected_brokers", disconnected, fmt.Sprint(len(all))),
+	)
+
+	return result
+}
+
+// collectErrors returns the collection phases that failed, sorted by name.
+func collectErrors(registry *metrics.Registry) []string {
+	phases := []string{}
+	for _, m := range registry.Snapshot() {
+		if m.Name == "monitor.collect.errors" && m.Values["count"] > 0 {
+			phases = append(phases, m.Tags["phase"])
+		}
+	}
+	sort.Strings(phases)
+
+	return phases
+}
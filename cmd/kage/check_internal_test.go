@@ -0,0 +1,103 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/msales/kage"
+	"github.com/msales/kage/kafka"
+	"github.com/msales/kage/metrics"
+	"github.com/msales/kage/store"
+	"github.com/msales/kage/testutil/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func newCheckApplication(md store.BrokerMetadata, co store.ConsumerOffsets, brokers []kafka.Broker) *kage.Application {
+	memStore := new(mocks.MockStore)
+	memStore.On("BrokerMetadata").Return(md)
+	memStore.On("ConsumerOffsets").Return(co)
+
+	monitor := new(mocks.MockMonitor)
+	monitor.On("Brokers").Return(brokers)
+
+	app := kage.NewApplication()
+	app.Store = memStore
+	app.Monitor = monitor
+	app.Metrics = metrics.NewRegistry()
+
+	return app
+}
+
+func TestCheckThreshold_State(t *testing.T) {
+	tests := []struct {
+		threshold checkThreshold
+		value     int64
+		state     int
+	}{
+		{checkThreshold{Warning: 10, Critical: 20}, 10, checkOK},
+		{checkThreshold{Warning: 10, Critical: 20}, 11, checkWarning},
+		{checkThreshold{Warning: 10, Critical: 20}, 21, checkCritical},
+		{checkThreshold{Warning: -1, Critical: 0}, 1, checkCritical},
+		{checkThreshold{Warning: -1, Critical: -1}, 100, checkOK},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.state, tt.threshold.State(tt.value))
+	}
+}
+
+func TestCheckThreshold_Perfdata(t *testing.T) {
+	assert.Equal(t, "max_lag=5;10;20;0", checkThreshold{Warning: 10, Critical: 20}.Perfdata("max_lag", 5, ""))
+	assert.Equal(t, "disconnected_brokers=1;;0;0;3", checkThreshold{Warning: -1, Critical: 0}.Perfdata("disconnected_brokers", 1, "3"))
+}
+
+func TestCheck(t *testing.T) {
+	app := newCheckApplication(
+		store.BrokerMetadata{"foo": {{Leader: 1}, {Leader: -1}}},
+		store.ConsumerOffsets{
+			"billing": {"foo": {{Lag: 10}, {Lag: 5}}},
+			"other":   {"foo": {{Lag: 100}, nil}},
+		},
+		[]kafka.Broker{{ID: 1, Connected: true}, {ID: 2, Connected: true}},
+	)
+
+	result := check(app, "bill*",
+		checkThreshold{Warning: 10, Critical: 20},
+		checkThreshold{Warning: -1, Critical: 0},
+		checkThreshold{Warning: -1, Critical: 0},
+	)
+
+	assert.Equal(t, checkCritical, result.State)
+	assert.Equal(t, "KAGE CRITICAL - group billing lag 15, 1 offline partitions | max_lag=15;10;20;0 offline_partitions=1;;0;0 disconnected_brokers=0;;0;0;2", result.String())
+}
+
+func TestCheck_CollectErrors(t *testing.T) {
+	app := newCheckApplication(
+		store.BrokerMetadata{"foo": {{Leader: 1}}},
+		store.ConsumerOffsets{},
+		[]kafka.Broker{{ID: 1, Connected: true}, {ID: 2, Connected: false}},
+	)
+	app.Metrics.Counter("monitor.collect.errors", "phase", "consumer_offsets").Inc(1)
+
+	result := check(app, "",
+		checkThreshold{Warning: -1, Critical: -1},
+		checkThreshold{Warning: -1, Critical: 0},
+		checkThreshold{Warning: -1, Critical: 0},
+	)
+
+	assert.Equal(t, checkCritical, result.State)
+	assert.Equal(t, "KAGE CRITICAL - 1 of 2 brokers disconnected, cannot collect consumer_offsets | max_lag=0;;;0 offline_partitions=0;;0;0 disconnected_brokers=1;;0;0;2", result.String())
+}
+
+func TestCheck_NoMetadata(t *testing.T) {
+	app := newCheckApplication(store.BrokerMetadata{}, store.ConsumerOffsets{}, []kafka.Broker{{ID: 1, Connected: false}})
+	app.Metrics.Counter("monitor.collect.errors", "phase", "broker_metadata").Inc(1)
+
+	result := check(app, "",
+		checkThreshold{Warning: -1, Critical: -1},
+		checkThreshold{Warning: -1, Critical: 0},
+		checkThreshold{Warning: -1, Critical: 0},
+	)
+
+	assert.Equal(t, checkUnknown, result.State)
+	assert.Equal(t, "KAGE UNKNOWN - no brokers or metadata to check, cannot collect broker_metadata", result.String())
+}
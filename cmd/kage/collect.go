@@ -0,0 +1,73 @@
+package main
+
+import (
+	"github.com/msales/kage"
+	"github.com/msales/kage/kafka"
+	"github.com/msales/kage/metrics"
+	"github.com/msales/kage/store"
+	"gopkg.in/urfave/cli.v1"
+)
+
+// collectOnce creates an application without reporters and collects the
+// state of the Kafka cluster once. The collected state is in the store
+// when it returns.
+func collectOnce(c *cli.Context) (*kage.Application, error) {
+	logger, err := newLogger(c)
+	if err != nil {
+		return nil, err
+	}
+
+	registry := metrics.NewRegistry()
+
+	memStore, err := store.New(store.Metrics(registry))
+	if err != nil {
+		return nil, err
+	}
+
+	// The state is applied in order, so the consumer offsets find the
+	// broker offsets they are compared to. A flushed channel is closed
+	// once the state sent before it has been applied.
+	stateCh := make(chan interface{}, 1000)
+	go func() {
+		for v := range stateCh {
+			if flushed, ok := v.(chan struct{}); ok {
+				close(flushed)
+				continue
+			}
+
+			memStore.SetState(v)
+		}
+	}()
+
+	monitor, err := kafka.New(
+		kafka.Brokers(c.StringSlice(FlagKafkaBrokers)),
+		kafka.IgnoreTopics(c.StringSlice(FlagKafkaIgnoreTopics)),
+		kafka.IgnoreGroups(c.StringSlice(FlagKafkaIgnoreGroups)),
+		kafka.StateChannel(stateCh),
+		kafka.Metrics(registry),
+		kafka.Log(logger),
+		kafka.NoInitialCollect(),
+	)
+	if err != nil {
+		memStore.Close()
+		return nil, err
+	}
+
+	app := kage.NewApplication()
+	app.Store = memStore
+	app.Reporters = &kage.Reporters{}
+	app.Monitor = monitor
+	app.Metrics = registry
+	app.Logger = logger
+
+	// The brokers are connected lazily, so they are connected up
+	// front for the broker statuses to be accurate.
+	monitor.ConnectBrokers()
+	app.Collect()
+
+	flushed := make(chan struct{})
+	stateCh <- flushed
+	<-flushed
+
+	return app, nil
+}
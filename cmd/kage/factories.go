@@ -1,14 +1,20 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"io/ioutil"
+	"net"
 	"net/url"
 	"os"
 	"strings"
 
+	"github.com/Shopify/sarama"
 	"github.com/influxdata/influxdb/client/v2"
 	"github.com/msales/kage"
+	"github.com/msales/kage/alert"
 	"github.com/msales/kage/kafka"
+	"github.com/msales/kage/metrics"
 	"github.com/msales/kage/reporter"
 	"github.com/msales/kage/store"
 	"github.com/msales/kage/utils"
@@ -24,12 +30,14 @@ func newApplication(c *cli.Context) (*kage.Application, error) {
 		return nil, err
 	}
 
-	memStore, err := store.New()
+	registry := metrics.NewRegistry()
+
+	memStore, err := store.New(store.Metrics(registry))
 	if err != nil {
 		return nil, err
 	}
 
-	reporters, err := newReporters(c, logger)
+	reporters, err := newReporters(c, registry, logger)
 	if err != nil {
 		return nil, err
 	}
@@ -39,6 +47,7 @@ func newApplication(c *cli.Context) (*kage.Application, error) {
 		kafka.IgnoreTopics(c.StringSlice(FlagKafkaIgnoreTopics)),
 		kafka.IgnoreGroups(c.StringSlice(FlagKafkaIgnoreGroups)),
 		kafka.StateChannel(memStore.Channel()),
+		kafka.Metrics(registry),
 		kafka.Log(logger),
 	)
 	if err != nil {
@@ -49,34 +58,159 @@ func newApplication(c *cli.Context) (*kage.Application, error) {
 	app.Store = memStore
 	app.Reporters = reporters
 	app.Monitor = monitor
+	app.Metrics = registry
 	app.Logger = logger
 
+	if c.String(FlagAlerts) != "" {
+		app.Alerts, err = newAlertEngine(c, logger)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return app, nil
 }
 
+// Alerts ==================================
+
+// newAlertEngine creates an alerting rules engine from the config.
+func newAlertEngine(c *cli.Context, logger log15.Logger) (*alert.Engine, error) {
+	rules, err := alert.LoadRules(c.String(FlagAlerts))
+	if err != nil {
+		return nil, err
+	}
+
+	dispatchers := []*alert.Dispatcher{}
+
+	if addr := c.String(FlagAlertsAlertmanager); addr != "" {
+		dispatchers = append(dispatchers, alert.NewDispatcher(
+			"alertmanager",
+			alert.NewAlertmanagerNotifier(addr),
+			alert.ResendInterval(c.Duration(FlagAlertsAlertmanagerResendInterval)),
+			alert.DispatcherLog(logger),
+		))
+	}
+
+	if url := c.String(FlagAlertsWebhook); url != "" {
+		text := alert.DefaultWebhookTemplate
+		if path := c.String(FlagAlertsWebhookTemplate); path != "" {
+			b, err := ioutil.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			text = string(b)
+		}
+
+		tmpl, err := alert.ParseWebhookTemplate(text)
+		if err != nil {
+			return nil, err
+		}
+
+		dispatchers = append(dispatchers, alert.NewDispatcher(
+			"webhook",
+			alert.NewWebhookNotifier(url,
+				alert.WebhookTemplate(tmpl),
+				alert.WebhookHeaders(utils.SplitMap(c.StringSlice(FlagAlertsWebhookHeaders), "=")),
+			),
+			alert.ResendInterval(c.Duration(FlagAlertsWebhookResendInterval)),
+			alert.DispatcherLog(logger),
+		))
+	}
+
+	return alert.NewEngine(rules, alert.Dispatchers(dispatchers...), alert.Log(logger)), nil
+}
+
 // Reporters ===============================
 
 // newReporters creates reporters from the config.
-func newReporters(c *cli.Context, logger log15.Logger) (*kage.Reporters, error) {
+func newReporters(c *cli.Context, registry *metrics.Registry, logger log15.Logger) (*kage.Reporters, error) {
 	rs := &kage.Reporters{}
 
+	// The filters are keyed by reporter name, so they must name a configured reporter
+	names := map[string]bool{}
 	for _, name := range c.StringSlice(FlagReporters) {
+		names[name] = true
+	}
+
+	filters := map[string]map[string][]string{}
+	for _, flag := range []string{FlagReportersIncludeTopics, FlagReportersExcludeTopics, FlagReportersIncludeGroups, FlagReportersExcludeGroups} {
+		filter, err := utils.SplitMapSlice(c.StringSlice(flag), "=")
+		if err != nil {
+			return nil, fmt.Errorf("--%s: %s, expected 'reporter=pattern'", flag, err)
+		}
+
+		for name := range filter {
+			if !names[name] {
+				return nil, fmt.Errorf("--%s: unknown reporter \"%s\"", flag, name)
+			}
+		}
+		filters[flag] = filter
+	}
+	includeTopics := filters[FlagReportersIncludeTopics]
+	excludeTopics := filters[FlagReportersExcludeTopics]
+	includeGroups := filters[FlagReportersIncludeGroups]
+	excludeGroups := filters[FlagReportersExcludeGroups]
+
+	for _, name := range c.StringSlice(FlagReporters) {
+		var r kage.Reporter
 		switch name {
 		case "influx":
-			r, err := newInfluxReporter(c, logger)
+			var err error
+			r, err = newInfluxReporter(c, logger)
+			if err != nil {
+				return nil, err
+			}
+
+		case "statsd":
+			var err error
+			r, err = newStatsdReporter(c, logger)
+			if err != nil {
+				return nil, err
+			}
+
+		case "graphite":
+			r = newGraphiteReporter(c, logger)
+
+		case "kafka":
+			var err error
+			r, err = newKafkaReporter(c, logger)
+			if err != nil {
+				return nil, err
+			}
+
+		case "otlp":
+			r = newOTLPReporter(c, logger)
+
+		case "file":
+			var err error
+			r, err = newFileReporter(c, logger)
 			if err != nil {
 				return nil, err
 			}
-			rs.Add(name, r)
-			break
 
 		case "stdout":
-			r := reporter.NewConsoleReporter(os.Stdout)
-			rs.Add(name, r)
+			var err error
+			r, err = newConsoleReporter(c, logger)
+			if err != nil {
+				return nil, err
+			}
 
 		default:
 			return nil, fmt.Errorf("unknown reporter \"%s\"", name)
 		}
+
+		r = kage.NewFilterReporter(r,
+			kage.IncludeTopics(includeTopics[name]...),
+			kage.ExcludeTopics(excludeTopics[name]...),
+			kage.IncludeGroups(includeGroups[name]...),
+			kage.ExcludeGroups(excludeGroups[name]...),
+		)
+		r = kage.NewRetryReporter(r,
+			kage.ReportTimeout(c.Duration(FlagReportersTimeout)),
+			kage.RetryBuffer(c.Int(FlagReportersRetryBuffer)),
+			kage.RetryMetrics(name, registry),
+		)
+		rs.Add(name, kage.NewInstrumentedReporter(name, r, registry))
 	}
 
 	return rs, nil
@@ -89,33 +223,188 @@ func newInfluxReporter(c *cli.Context, logger log15.Logger) (kage.Reporter, erro
 		return nil, err
 	}
 
-	if dsn.User == nil {
-		dsn.User = &url.Userinfo{}
+	// Only the InfluxDB 1.x http API writes to a retention policy
+	policy, policies := c.String(FlagInfluxPolicy), utils.SplitMap(c.StringSlice(FlagInfluxPolicies), "=")
+	if dsn.Scheme != "http" && dsn.Scheme != "https" && (policy != "" || len(policies) > 0) {
+		return nil, fmt.Errorf("influx: retention policies are not supported by \"%s\" DSNs", dsn.Scheme)
 	}
 
-	addr := dsn.Scheme + "://" + dsn.Host
-	username := dsn.User.Username()
-	password, _ := dsn.User.Password()
+	influx, db, err := newInfluxClient(dsn)
+	if err != nil {
+		return nil, err
+	}
 
-	influx, err := client.NewHTTPClient(client.HTTPConfig{
-		Addr:     addr,
-		Username: username,
-		Password: password,
-	})
+	aggregation, err := reporter.ParseAggregation(c.String(FlagInfluxAggregation))
 	if err != nil {
 		return nil, err
 	}
-	db := strings.Trim(dsn.Path, "/")
+
+	measurements := utils.SplitMap(c.StringSlice(FlagInfluxMeasurements), "=")
+	if err := reporter.CheckInfluxReports(measurements); err != nil {
+		return nil, fmt.Errorf("--%s: %s", FlagInfluxMeasurements, err)
+	}
+
+	if err := reporter.CheckInfluxReports(policies); err != nil {
+		return nil, fmt.Errorf("--%s: %s", FlagInfluxPolicies, err)
+	}
 
 	return reporter.NewInfluxReporter(influx,
 		reporter.Database(db),
+		reporter.Aggregation(aggregation),
 		reporter.Metric(c.String(FlagInfluxMetric)),
-		reporter.Policy(c.String(FlagInfluxPolicy)),
+		reporter.Measurements(measurements),
+		reporter.Policy(policy),
+		reporter.Policies(policies),
 		reporter.Tags(utils.SplitMap(c.StringSlice(FlagInfluxTags), "=")),
+		reporter.AsTags(c.StringSlice(FlagInfluxAsTags)...),
+		reporter.AsFields(c.StringSlice(FlagInfluxAsFields)...),
 		reporter.Log(logger),
 	), nil
 }
 
+// newInfluxClient creates an InfluxDB client from the DSN scheme, returning
+// the client and the database to write to.
+func newInfluxClient(dsn *url.URL) (client.Client, string, error) {
+	if dsn.User == nil {
+		dsn.User = &url.Userinfo{}
+	}
+
+	switch dsn.Scheme {
+	case "http", "https":
+		username := dsn.User.Username()
+		password, _ := dsn.User.Password()
+
+		influx, err := client.NewHTTPClient(client.HTTPConfig{
+			Addr:     dsn.Scheme + "://" + dsn.Host,
+			Username: username,
+			Password: password,
+		})
+		return influx, strings.Trim(dsn.Path, "/"), err
+
+	case "influx2", "influx2s":
+		scheme := "http"
+		if dsn.Scheme == "influx2s" {
+			scheme = "https"
+		}
+
+		parts := strings.SplitN(strings.Trim(dsn.Path, "/"), "/", 2)
+		if len(parts) != 2 {
+			return nil, "", errors.New("influx: expected /org/bucket in the DSN path")
+		}
+
+		influx, err := reporter.NewInfluxV2Client(reporter.InfluxV2Config{
+			Addr:   scheme + "://" + dsn.Host,
+			Token:  dsn.User.Username(),
+			Org:    parts[0],
+			Bucket: parts[1],
+		})
+		return influx, parts[1], err
+
+	case "udp":
+		influx, err := client.NewUDPClient(client.UDPConfig{
+			Addr: dsn.Host,
+		})
+		return influx, "", err
+
+	default:
+		return nil, "", fmt.Errorf("influx: unknown DSN scheme \"%s\"", dsn.Scheme)
+	}
+}
+
+// newStatsdReporter create a new StatsD reporter.
+func newStatsdReporter(c *cli.Context, logger log15.Logger) (kage.Reporter, error) {
+	conn, err := net.Dial("udp", c.String(FlagStatsd))
+	if err != nil {
+		return nil, err
+	}
+
+	return reporter.NewStatsdReporter(conn,
+		reporter.StatsdPrefix(c.String(FlagStatsdPrefix)),
+		reporter.StatsdTags(utils.SplitMap(c.StringSlice(FlagStatsdTags), "=")),
+		reporter.StatsdLog(logger),
+	), nil
+}
+
+// newGraphiteReporter create a new Graphite reporter.
+func newGraphiteReporter(c *cli.Context, logger log15.Logger) kage.Reporter {
+	return reporter.NewGraphiteReporter(c.String(FlagGraphite),
+		reporter.GraphitePrefix(c.String(FlagGraphitePrefix)),
+		reporter.GraphiteTemplate(c.String(FlagGraphiteTemplate)),
+		reporter.GraphiteLog(logger),
+	)
+}
+
+// newKafkaReporter create a new Kafka reporter.
+func newKafkaReporter(c *cli.Context, logger log15.Logger) (kage.Reporter, error) {
+	brokers := c.StringSlice(FlagKafkaReporterBrokers)
+	if len(brokers) == 0 {
+		brokers = c.StringSlice(FlagKafkaBrokers)
+	}
+
+	config := sarama.NewConfig()
+	config.Version = sarama.V0_10_1_0
+	config.Producer.Return.Successes = true
+
+	producer, err := sarama.NewSyncProducer(brokers, config)
+	if err != nil {
+		return nil, err
+	}
+
+	return reporter.NewKafkaReporter(producer,
+		reporter.KafkaTopic(c.String(FlagKafkaReporterTopic)),
+		reporter.KafkaLog(logger),
+	), nil
+}
+
+// newOTLPReporter create a new OpenTelemetry reporter.
+func newOTLPReporter(c *cli.Context, logger log15.Logger) kage.Reporter {
+	cluster := c.String(FlagOTLPCluster)
+	if cluster == "" {
+		cluster = strings.Join(c.StringSlice(FlagKafkaBrokers), ",")
+	}
+
+	return reporter.NewOTLPReporter(c.String(FlagOTLP),
+		reporter.OTLPCluster(cluster),
+		reporter.OTLPAttributes(utils.SplitMap(c.StringSlice(FlagOTLPAttributes), "=")),
+		reporter.OTLPHeaders(utils.SplitMap(c.StringSlice(FlagOTLPHeaders), "=")),
+		reporter.OTLPLog(logger),
+	)
+}
+
+// newFileReporter create a new JSON Lines file reporter.
+func newFileReporter(c *cli.Context, logger log15.Logger) (kage.Reporter, error) {
+	f, err := reporter.NewRotatingFile(
+		c.String(FlagFile),
+		int64(c.Int(FlagFileMaxSize))*1024*1024,
+		c.Duration(FlagFileRotateInterval),
+		reporter.RotatingFileLog(logger),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return reporter.NewJSONReporter(f, reporter.JSONLog(logger)), nil
+}
+
+// newConsoleReporter create a new console reporter.
+func newConsoleReporter(c *cli.Context, logger log15.Logger) (kage.Reporter, error) {
+	switch format := c.String(FlagConsoleFormat); format {
+	case "text":
+		aggregation, err := reporter.ParseAggregation(c.String(FlagConsoleAggregation))
+		if err != nil {
+			return nil, err
+		}
+
+		return reporter.NewConsoleReporter(os.Stdout, reporter.ConsoleAggregation(aggregation)), nil
+
+	case "json":
+		return reporter.NewJSONReporter(os.Stdout, reporter.JSONLog(logger)), nil
+
+	default:
+		return nil, fmt.Errorf("unknown console format \"%s\"", format)
+	}
+}
+
 // Logger ==================================
 
 // newLogger creates a new logger from config.
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/msales/kage/store"
+	"github.com/ryanuber/go-glob"
+	"gopkg.in/urfave/cli.v1"
+)
+
+type partitionLag struct {
+	Group     string `json:"group"`
+	Topic     string `json:"topic"`
+	Partition int    `json:"partition"`
+	Offset    int64  `json:"offset"`
+	Lag       int64  `json:"lag"`
+}
+
+func runLag(c *cli.Context) {
+	app, err := collectOnce(c)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	lags := partitionLags(app.Store.ConsumerOffsets(), c.String(FlagLagGroup), c.String(FlagLagTopic))
+
+	if c.Bool(FlagLagJSON) {
+		err = json.NewEncoder(os.Stdout).Encode(lags)
+	} else {
+		err = writeLagTable(os.Stdout, lags)
+	}
+
+	// The application is closed before exiting, as log.Fatal skips deferred calls
+	app.Close()
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// partitionLags returns the partition lags of the groups and topics matching
+// the patterns, sorted by group, topic and partition.
+func partitionLags(o store.ConsumerOffsets, group, topic string) []partitionLag {
+	lags := []partitionLag{}
+	for g, topics := range o {
+		if group != "" && !glob.Glob(group, g) {
+			continue
+		}
+
+		for t, partitions := range topics {
+			if topic != "" && !glob.Glob(topic, t) {
+				continue
+			}
+
+			for p, offset := range partitions {
+				if offset == nil {
+					continue
+				}
+
+				lags = append(lags, partitionLag{Group: g, Topic: t, Partition: p, Offset: offset.Offset, Lag: offset.Lag})
+			}
+		}
+	}
+
+	sort.Slice(lags, func(i, j int) bool {
+		if lags[i].Group != lags[j].Group {
+			return lags[i].Group < lags[j].Group
+		}
+		if lags[i].Topic != lags[j].Topic {
+			return lags[i].Topic < lags[j].Topic
+		}
+		return lags[i].Partition < lags[j].Partition
+	})
+
+	return lags
+}
+
+func writeLagTable(w io.Writer, lags []partitionLag) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "GROUP\tTOPIC\tPARTITION\tOFFSET\tLAG")
+	for _, l := range lags {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d\n", l.Group, l.Topic, l.Partition, l.Offset, l.Lag)
+	}
+
+	return tw.Flush()
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/msales/kage/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPartitionLags(t *testing.T) {
+	offsets := store.ConsumerOffsets{
+		"foo": {
+			"test":  {{Offset: 10, Lag: 1}, nil, {Offset: 30, Lag: 3}},
+			"other": {{Offset: 40, Lag: 4}},
+		},
+		"bar": {"test": {{Offset: 50, Lag: 5}}},
+	}
+
+	assert.Equal(t, []partitionLag{
+		{Group: "bar", Topic: "test", Partition: 0, Offset: 50, Lag: 5},
+		{Group: "foo", Topic: "test", Partition: 0, Offset: 10, Lag: 1},
+		{Group: "foo", Topic: "test", Partition: 2, Offset: 30, Lag: 3},
+	}, partitionLags(offsets, "", "te*"))
+
+	assert.Equal(t, []partitionLag{
+		{Group: "foo", Topic: "other", Partition: 0, Offset: 40, Lag: 4},
+	}, partitionLags(offsets, "foo", "other"))
+
+	assert.Equal(t, []partitionLag{}, partitionLags(offsets, "baz", ""))
+}
+
+func TestWriteLagTable(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := writeLagTable(&buf, []partitionLag{{Group: "foo", Topic: "test", Partition: 1, Offset: 10, Lag: 2}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "GROUP  TOPIC  PARTITION  OFFSET  LAG\nfoo    test   1          10      2\n", buf.String())
+}
@@ -2,7 +2,9 @@ package main
 
 import (
 	"os"
+	"time"
 
+	"github.com/msales/kage/reporter"
 	"gopkg.in/urfave/cli.v1"
 )
 
@@ -18,15 +20,73 @@ const (
 	FlagKafkaIgnoreTopics = "kafka.ignore-topics"
 	FlagKafkaIgnoreGroups = "kafka.ignore-groups"
 
-	FlagReporters = "reporters"
+	FlagReporters              = "reporters"
+	FlagReportersTimeout       = "reporters.timeout"
+	FlagReportersRetryBuffer   = "reporters.retry-buffer"
+	FlagReportersIncludeTopics = "reporters.include-topics"
+	FlagReportersExcludeTopics = "reporters.exclude-topics"
+	FlagReportersIncludeGroups = "reporters.include-groups"
+	FlagReportersExcludeGroups = "reporters.exclude-groups"
 
-	FlagInflux       = "influx"
-	FlagInfluxMetric = "influx.metric"
-	FlagInfluxPolicy = "influx.policy"
-	FlagInfluxTags   = "influx.tags"
+	FlagInflux             = "influx"
+	FlagInfluxMetric       = "influx.metric"
+	FlagInfluxMeasurements = "influx.measurements"
+	FlagInfluxPolicy       = "influx.policy"
+	FlagInfluxPolicies     = "influx.policies"
+	FlagInfluxTags         = "influx.tags"
+	FlagInfluxAsTags       = "influx.as-tags"
+	FlagInfluxAsFields     = "influx.as-fields"
+	FlagInfluxAggregation  = "influx.aggregation"
 
-	FlagServer = "server"
-	FlagPort   = "port"
+	FlagStatsd       = "statsd"
+	FlagStatsdPrefix = "statsd.prefix"
+	FlagStatsdTags   = "statsd.tags"
+
+	FlagGraphite         = "graphite"
+	FlagGraphitePrefix   = "graphite.prefix"
+	FlagGraphiteTemplate = "graphite.template"
+
+	FlagKafkaReporterBrokers = "kafka-reporter.brokers"
+	FlagKafkaReporterTopic   = "kafka-reporter.topic"
+
+	FlagOTLP           = "otlp"
+	FlagOTLPCluster    = "otlp.cluster"
+	FlagOTLPAttributes = "otlp.attributes"
+	FlagOTLPHeaders    = "otlp.headers"
+
+	FlagConsoleFormat      = "console.format"
+	FlagConsoleAggregation = "console.aggregation"
+
+	FlagFile               = "file"
+	FlagFileMaxSize        = "file.max-size"
+	FlagFileRotateInterval = "file.rotate-interval"
+
+	FlagAlerts                           = "alerts"
+	FlagAlertsAlertmanager               = "alerts.alertmanager"
+	FlagAlertsAlertmanagerResendInterval = "alerts.alertmanager.resend-interval"
+	FlagAlertsWebhook                    = "alerts.webhook"
+	FlagAlertsWebhookTemplate            = "alerts.webhook.template"
+	FlagAlertsWebhookHeaders             = "alerts.webhook.headers"
+	FlagAlertsWebhookResendInterval      = "alerts.webhook.resend-interval"
+
+	FlagLagGroup = "group"
+	FlagLagTopic = "topic"
+	FlagLagJSON  = "json"
+
+	FlagCheckLagWarning      = "lag.warning"
+	FlagCheckLagCritical     = "lag.critical"
+	FlagCheckOfflineWarning  = "offline.warning"
+	FlagCheckOfflineCritical = "offline.critical"
+	FlagCheckBrokersWarning  = "brokers.warning"
+	FlagCheckBrokersCritical = "brokers.critical"
+
+	FlagServer         = "server"
+	FlagPort           = "port"
+	FlagServerTLSCert  = "server.tls-cert"
+	FlagServerTLSKey   = "server.tls-key"
+	FlagServerUsername = "server.username"
+	FlagServerPassword = "server.password"
+	FlagServerToken    = "server.token"
 )
 
 // Version is the compiled application version.
@@ -50,6 +110,22 @@ var commonFlags = []cli.Flag{
 		Usage:  "Specify the log level (options: \"debug\", \"info\", \"warn\", \"error\")",
 		EnvVar: "KAGE_LOG_LEVEL",
 	},
+
+	cli.StringSliceFlag{
+		Name:   FlagKafkaBrokers,
+		Usage:  "Specify the Kafka seed brokers",
+		EnvVar: "KAGE_KAFKA_BROKERS",
+	},
+	cli.StringSliceFlag{
+		Name:   FlagKafkaIgnoreTopics,
+		Usage:  "Specify the Kafka topic patterns to ignore (may contain wildcards)",
+		EnvVar: "KAGE_KAFKA_IGNORE_TOPICS",
+	},
+	cli.StringSliceFlag{
+		Name:   FlagKafkaIgnoreGroups,
+		Usage:  "Specify the Kafka group patterns to ignore (may contain wildcards)",
+		EnvVar: "KAGE_KAFKA_IGNORE_GROUPS",
+	},
 }
 
 var commands = []cli.Command{
@@ -58,31 +134,47 @@ var commands = []cli.Command{
 		Usage: "Run the kage agent",
 		Flags: append([]cli.Flag{
 			cli.StringSliceFlag{
-				Name:   FlagKafkaBrokers,
-				Usage:  "Specify the Kafka seed brokers",
-				EnvVar: "KAGE_KAFKA_BROKERS",
+				Name:   FlagReporters,
+				Value:  &cli.StringSlice{"stdout"},
+				Usage:  "Specify the reporters to use (options: \"file\", \"graphite\", \"influx\", \"kafka\", \"otlp\", \"statsd\", \"stdout\")",
+				EnvVar: "KAGE_REPORTERS",
+			},
+			cli.DurationFlag{
+				Name:   FlagReportersTimeout,
+				Value:  10 * time.Second,
+				Usage:  "Specify the time a reporter may take to send a report",
+				EnvVar: "KAGE_REPORTERS_TIMEOUT",
+			},
+			cli.IntFlag{
+				Name:   FlagReportersRetryBuffer,
+				Value:  10,
+				Usage:  "Specify the number of failed reports kept per reporter to retry",
+				EnvVar: "KAGE_REPORTERS_RETRY_BUFFER",
+			},
+			cli.StringSliceFlag{
+				Name:   FlagReportersIncludeTopics,
+				Usage:  "Specify the topic patterns a reporter reports (e.g. \"statsd=orders-*\")",
+				EnvVar: "KAGE_REPORTERS_INCLUDE_TOPICS",
 			},
 			cli.StringSliceFlag{
-				Name:   FlagKafkaIgnoreTopics,
-				Usage:  "Specify the Kafka topic patterns to ignore (may contain wildcards)",
-				EnvVar: "KAGE_KAFKA_IGNORE_TOPICS",
+				Name:   FlagReportersExcludeTopics,
+				Usage:  "Specify the topic patterns a reporter does not report (e.g. \"statsd=__*\")",
+				EnvVar: "KAGE_REPORTERS_EXCLUDE_TOPICS",
 			},
 			cli.StringSliceFlag{
-				Name:   FlagKafkaIgnoreGroups,
-				Usage:  "Specify the Kafka group patterns to ignore (may contain wildcards)",
-				EnvVar: "KAGE_KAFKA_IGNORE_GROUPS",
+				Name:   FlagReportersIncludeGroups,
+				Usage:  "Specify the group patterns a reporter reports (e.g. \"statsd=billing-*\")",
+				EnvVar: "KAGE_REPORTERS_INCLUDE_GROUPS",
 			},
-
 			cli.StringSliceFlag{
-				Name:   FlagReporters,
-				Value:  &cli.StringSlice{"stdout"},
-				Usage:  "Specify the reporters to use (options: \"influx\", \"stdout\")",
-				EnvVar: "KAGE_REPORTERS",
+				Name:   FlagReportersExcludeGroups,
+				Usage:  "Specify the group patterns a reporter does not report (e.g. \"statsd=test-*\")",
+				EnvVar: "KAGE_REPORTERS_EXCLUDE_GROUPS",
 			},
 
 			cli.StringFlag{
 				Name:   FlagInflux,
-				Usage:  "Specify the InfluxDB DSN (e.g. \"http://user:pass@ip:port/database\")",
+				Usage:  "Specify the InfluxDB DSN (e.g. \"http://user:pass@ip:port/database\", \"influx2://token@ip:port/org/bucket\" or \"udp://ip:port\")",
 				EnvVar: "KAGE_INFLUX",
 			},
 			cli.StringFlag{
@@ -91,16 +183,180 @@ var commands = []cli.Command{
 				Usage:  "Specify the InfluxDB metric name",
 				EnvVar: "KAGE_INFLUX_METRIC",
 			},
+			cli.StringSliceFlag{
+				Name:   FlagInfluxMeasurements,
+				Usage:  "Specify the InfluxDB metric name of a report type (e.g. \"consumer_offset=kafka_lag\")",
+				EnvVar: "KAGE_INFLUX_MEASUREMENTS",
+			},
 			cli.StringFlag{
 				Name:   FlagInfluxPolicy,
 				Usage:  "Specify the InfluxDB metric policy",
 				EnvVar: "KAGE_INFLUX_POLICY",
 			},
+			cli.StringSliceFlag{
+				Name:   FlagInfluxPolicies,
+				Usage:  "Specify the InfluxDB metric policy of a report type (e.g. \"metric=one_week\")",
+				EnvVar: "KAGE_INFLUX_POLICIES",
+			},
 			cli.StringSliceFlag{
 				Name:   FlagInfluxTags,
 				Usage:  "Specify additions tags to add to all metrics (e.g. \"tag1=value\")",
 				EnvVar: "KAGE_INFLUX_TAGS",
 			},
+			cli.StringSliceFlag{
+				Name:   FlagInfluxAsTags,
+				Usage:  "Specify the values to write as tags instead of fields (e.g. \"leaders\")",
+				EnvVar: "KAGE_INFLUX_AS_TAGS",
+			},
+			cli.StringSliceFlag{
+				Name:   FlagInfluxAsFields,
+				Usage:  "Specify the values to write as fields instead of tags (e.g. \"partition\")",
+				EnvVar: "KAGE_INFLUX_AS_FIELDS",
+			},
+			cli.StringFlag{
+				Name:   FlagInfluxAggregation,
+				Value:  "partition",
+				Usage:  "Specify the InfluxDB aggregation level (options: \"group\", \"partition\", \"topic\")",
+				EnvVar: "KAGE_INFLUX_AGGREGATION",
+			},
+
+			cli.StringFlag{
+				Name:   FlagStatsd,
+				Usage:  "Specify the StatsD address (e.g. \"ip:port\")",
+				EnvVar: "KAGE_STATSD",
+			},
+			cli.StringFlag{
+				Name:   FlagStatsdPrefix,
+				Value:  "kafka",
+				Usage:  "Specify the StatsD metric prefix",
+				EnvVar: "KAGE_STATSD_PREFIX",
+			},
+			cli.StringSliceFlag{
+				Name:   FlagStatsdTags,
+				Usage:  "Specify additional DogStatsD tags to add to all metrics (e.g. \"tag1=value\")",
+				EnvVar: "KAGE_STATSD_TAGS",
+			},
+
+			cli.StringFlag{
+				Name:   FlagGraphite,
+				Usage:  "Specify the Graphite plaintext address (e.g. \"ip:port\")",
+				EnvVar: "KAGE_GRAPHITE",
+			},
+			cli.StringFlag{
+				Name:   FlagGraphitePrefix,
+				Value:  "kafka",
+				Usage:  "Specify the Graphite path prefix",
+				EnvVar: "KAGE_GRAPHITE_PREFIX",
+			},
+			cli.StringFlag{
+				Name:   FlagGraphiteTemplate,
+				Value:  reporter.DefaultGraphiteTemplate,
+				Usage:  "Specify the Graphite path template",
+				EnvVar: "KAGE_GRAPHITE_TEMPLATE",
+			},
+
+			cli.StringSliceFlag{
+				Name:   FlagKafkaReporterBrokers,
+				Usage:  "Specify the Kafka brokers to report to (defaults to the monitored brokers)",
+				EnvVar: "KAGE_KAFKA_REPORTER_BROKERS",
+			},
+			cli.StringFlag{
+				Name:   FlagKafkaReporterTopic,
+				Value:  "kage",
+				Usage:  "Specify the Kafka topic to report to",
+				EnvVar: "KAGE_KAFKA_REPORTER_TOPIC",
+			},
+
+			cli.StringFlag{
+				Name:   FlagOTLP,
+				Value:  reporter.DefaultOTLPEndpoint,
+				Usage:  "Specify the OTLP/HTTP metrics endpoint",
+				EnvVar: "KAGE_OTLP",
+			},
+			cli.StringFlag{
+				Name:   FlagOTLPCluster,
+				Usage:  "Specify the Kafka cluster name resource attribute (defaults to the monitored brokers)",
+				EnvVar: "KAGE_OTLP_CLUSTER",
+			},
+			cli.StringSliceFlag{
+				Name:   FlagOTLPAttributes,
+				Usage:  "Specify additional resource attributes to add to all metrics (e.g. \"attr1=value\")",
+				EnvVar: "KAGE_OTLP_ATTRIBUTES",
+			},
+			cli.StringSliceFlag{
+				Name:   FlagOTLPHeaders,
+				Usage:  "Specify additional headers to send to the OTLP endpoint (e.g. \"header1=value\")",
+				EnvVar: "KAGE_OTLP_HEADERS",
+			},
+
+			cli.StringFlag{
+				Name:   FlagConsoleFormat,
+				Value:  "text",
+				Usage:  "Specify the stdout reporter format (options: \"json\", \"text\")",
+				EnvVar: "KAGE_CONSOLE_FORMAT",
+			},
+			cli.StringFlag{
+				Name:   FlagConsoleAggregation,
+				Value:  "partition",
+				Usage:  "Specify the stdout reporter text aggregation level (options: \"group\", \"partition\", \"topic\")",
+				EnvVar: "KAGE_CONSOLE_AGGREGATION",
+			},
+
+			cli.StringFlag{
+				Name:   FlagFile,
+				Usage:  "Specify the path of the JSON Lines report file",
+				EnvVar: "KAGE_FILE",
+			},
+			cli.IntFlag{
+				Name:   FlagFileMaxSize,
+				Value:  100,
+				Usage:  "Specify the size in megabytes at which the report file is rotated (0 to disable)",
+				EnvVar: "KAGE_FILE_MAX_SIZE",
+			},
+			cli.DurationFlag{
+				Name:   FlagFileRotateInterval,
+				Value:  24 * time.Hour,
+				Usage:  "Specify the age at which the report file is rotated (0 to disable)",
+				EnvVar: "KAGE_FILE_ROTATE_INTERVAL",
+			},
+
+			cli.StringFlag{
+				Name:   FlagAlerts,
+				Usage:  "Specify the path of the JSON alerting rules file",
+				EnvVar: "KAGE_ALERTS",
+			},
+			cli.StringFlag{
+				Name:   FlagAlertsAlertmanager,
+				Usage:  "Specify the Alertmanager address to send alerts to (e.g. \"http://ip:9093\")",
+				EnvVar: "KAGE_ALERTS_ALERTMANAGER",
+			},
+			cli.DurationFlag{
+				Name:   FlagAlertsAlertmanagerResendInterval,
+				Value:  time.Minute,
+				Usage:  "Specify the interval firing alerts are sent to the Alertmanager again",
+				EnvVar: "KAGE_ALERTS_ALERTMANAGER_RESEND_INTERVAL",
+			},
+			cli.StringFlag{
+				Name:   FlagAlertsWebhook,
+				Usage:  "Specify the webhook url to send alerts to",
+				EnvVar: "KAGE_ALERTS_WEBHOOK",
+			},
+			cli.StringFlag{
+				Name:   FlagAlertsWebhookTemplate,
+				Usage:  "Specify the path of the webhook body template (defaults to the alerts as JSON)",
+				EnvVar: "KAGE_ALERTS_WEBHOOK_TEMPLATE",
+			},
+			cli.StringSliceFlag{
+				Name:   FlagAlertsWebhookHeaders,
+				Usage:  "Specify additional headers to send to the webhook (e.g. \"header1=value\")",
+				EnvVar: "KAGE_ALERTS_WEBHOOK_HEADERS",
+			},
+			cli.DurationFlag{
+				Name:   FlagAlertsWebhookResendInterval,
+				Value:  4 * time.Hour,
+				Usage:  "Specify the interval firing alerts are sent to the webhook again (0 to send once)",
+				EnvVar: "KAGE_ALERTS_WEBHOOK_RESEND_INTERVAL",
+			},
 
 			cli.BoolFlag{
 				Name:   FlagServer,
@@ -113,9 +369,94 @@ var commands = []cli.Command{
 				Usage:  "Specify the port to run the server on",
 				EnvVar: "KAGE_PORT",
 			},
+			cli.StringFlag{
+				Name:   FlagServerTLSCert,
+				Usage:  "Specify the TLS certificate file to serve https with",
+				EnvVar: "KAGE_SERVER_TLS_CERT",
+			},
+			cli.StringFlag{
+				Name:   FlagServerTLSKey,
+				Usage:  "Specify the TLS key file to serve https with",
+				EnvVar: "KAGE_SERVER_TLS_KEY",
+			},
+			cli.StringFlag{
+				Name:   FlagServerUsername,
+				Usage:  "Specify the basic authentication username of the server",
+				EnvVar: "KAGE_SERVER_USERNAME",
+			},
+			cli.StringFlag{
+				Name:   FlagServerPassword,
+				Usage:  "Specify the basic authentication password of the server",
+				EnvVar: "KAGE_SERVER_PASSWORD",
+			},
+			cli.StringFlag{
+				Name:   FlagServerToken,
+				Usage:  "Specify the bearer authentication token of the server",
+				EnvVar: "KAGE_SERVER_TOKEN",
+			},
 		}, commonFlags...),
 		Action: runServer,
 	},
+	{
+		Name:  "lag",
+		Usage: "Print the consumer group lag once",
+		Flags: append([]cli.Flag{
+			cli.StringFlag{
+				Name:  FlagLagGroup,
+				Usage: "Only print the consumer groups matching the pattern (may contain wildcards)",
+			},
+			cli.StringFlag{
+				Name:  FlagLagTopic,
+				Usage: "Only print the topics matching the pattern (may contain wildcards)",
+			},
+			cli.BoolFlag{
+				Name:  FlagLagJSON,
+				Usage: "Print the lag as JSON",
+			},
+		}, commonFlags...),
+		Action: runLag,
+	},
+	{
+		Name:  "check",
+		Usage: "Check the cluster once, with Nagios plugin output and exit codes",
+		Flags: append([]cli.Flag{
+			cli.StringFlag{
+				Name:  FlagLagGroup,
+				Usage: "Only check the consumer groups matching the pattern (may contain wildcards)",
+			},
+			cli.Int64Flag{
+				Name:  FlagCheckLagWarning,
+				Value: -1,
+				Usage: "Specify the group lag over which the check is warning (-1 to disable)",
+			},
+			cli.Int64Flag{
+				Name:  FlagCheckLagCritical,
+				Value: -1,
+				Usage: "Specify the group lag over which the check is critical (-1 to disable)",
+			},
+			cli.Int64Flag{
+				Name:  FlagCheckOfflineWarning,
+				Value: -1,
+				Usage: "Specify the offline partitions over which the check is warning (-1 to disable)",
+			},
+			cli.Int64Flag{
+				Name:  FlagCheckOfflineCritical,
+				Value: 0,
+				Usage: "Specify the offline partitions over which the check is critical (-1 to disable)",
+			},
+			cli.Int64Flag{
+				Name:  FlagCheckBrokersWarning,
+				Value: -1,
+				Usage: "Specify the disconnected brokers over which the check is warning (-1 to disable)",
+			},
+			cli.Int64Flag{
+				Name:  FlagCheckBrokersCritical,
+				Value: 0,
+				Usage: "Specify the disconnected brokers over which the check is critical (-1 to disable)",
+			},
+		}, commonFlags...),
+		Action: runCheck,
+	},
 }
 
 func main() {
@@ -39,14 +39,29 @@ func runServer(c *cli.Context) {
 
 	if c.Bool(FlagServer) {
 		port := c.String(FlagPort)
-		srv := newServer(app)
+		cert := c.String(FlagServerTLSCert)
+		key := c.String(FlagServerTLSKey)
+		if (cert == "") != (key == "") {
+			log.Fatal("both a TLS certificate and key are required to serve https")
+		}
+
+		srv := newServer(c, app)
 		h := http.Server{Addr: ":" + port, Handler: srv}
+		h.RegisterOnShutdown(srv.Close)
 		defer func() {
 			h.Shutdown(context.Background())
 		}()
 		go func() {
 			log.Printf("Starting on port %s.\n", port)
-			if err := h.ListenAndServe(); err != nil {
+
+			var err error
+			if cert != "" {
+				err = h.ListenAndServeTLS(cert, key)
+			} else {
+				err = h.ListenAndServe()
+			}
+
+			if err != nil {
 				if err != http.ErrServerClosed {
 					log.Fatal(err)
 				}
@@ -57,8 +72,18 @@ func runServer(c *cli.Context) {
 	<-catchOsSignals()
 }
 
-func newServer(app *kage.Application) http.Handler {
-	return server.New(app)
+func newServer(c *cli.Context, app *kage.Application) *server.Server {
+	opts := []server.OptFunc{}
+
+	if username := c.String(FlagServerUsername); username != "" {
+		opts = append(opts, server.BasicAuth(username, c.String(FlagServerPassword)))
+	}
+
+	if token := c.String(FlagServerToken); token != "" {
+		opts = append(opts, server.BearerToken(token))
+	}
+
+	return server.New(app, opts...)
 }
 
 // Wait for SIGTERM to end the application.
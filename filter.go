@@ -0,0 +1,178 @@
+package kage
+
+import (
+	"github.com/msales/kage/metrics"
+	"github.com/msales/kage/store"
+	"github.com/ryanuber/go-glob"
+)
+
+// FilterReporterFunc represents a configuration function for FilterReporter.
+type FilterReporterFunc func(r *FilterReporter)
+
+// IncludeTopics configures the topic patterns to report on a FilterReporter.
+func IncludeTopics(patterns ...string) FilterReporterFunc {
+	return func(r *FilterReporter) {
+		r.topics.include = patterns
+	}
+}
+
+// ExcludeTopics configures the topic patterns not to report on a FilterReporter.
+func ExcludeTopics(patterns ...string) FilterReporterFunc {
+	return func(r *FilterReporter) {
+		r.topics.exclude = patterns
+	}
+}
+
+// IncludeGroups configures the group patterns to report on a FilterReporter.
+func IncludeGroups(patterns ...string) FilterReporterFunc {
+	return func(r *FilterReporter) {
+		r.groups.include = patterns
+	}
+}
+
+// ExcludeGroups configures the group patterns not to report on a FilterReporter.
+func ExcludeGroups(patterns ...string) FilterReporterFunc {
+	return func(r *FilterReporter) {
+		r.groups.exclude = patterns
+	}
+}
+
+// FilterReporter removes the topics and groups not matching its patterns
+// from the snapshots before they are sent to a Reporter. Broker statuses
+// and internal metrics are not filtered.
+type FilterReporter struct {
+	reporter Reporter
+
+	topics patternFilter
+	groups patternFilter
+}
+
+// NewFilterReporter creates and returns a new FilterReporter.
+func NewFilterReporter(r Reporter, opts ...FilterReporterFunc) *FilterReporter {
+	fr := &FilterReporter{
+		reporter: r,
+	}
+
+	for _, o := range opts {
+		o(fr)
+	}
+
+	return fr
+}
+
+// ReportBrokerOffsets reports a snapshot of the broker offsets.
+func (r *FilterReporter) ReportBrokerOffsets(o *store.BrokerOffsets) error {
+	if r.topics.Empty() {
+		return r.reporter.ReportBrokerOffsets(o)
+	}
+
+	filtered := store.BrokerOffsets{}
+	for topic, partitions := range *o {
+		if r.topics.Match(topic) {
+			filtered[topic] = partitions
+		}
+	}
+
+	return r.reporter.ReportBrokerOffsets(&filtered)
+}
+
+// ReportBrokerMetadata reports a snapshot of the broker metadata.
+func (r *FilterReporter) ReportBrokerMetadata(m *store.BrokerMetadata) error {
+	if r.topics.Empty() {
+		return r.reporter.ReportBrokerMetadata(m)
+	}
+
+	filtered := store.BrokerMetadata{}
+	for topic, partitions := range *m {
+		if r.topics.Match(topic) {
+			filtered[topic] = partitions
+		}
+	}
+
+	return r.reporter.ReportBrokerMetadata(&filtered)
+}
+
+// ReportBrokerStatus reports the status of the brokers.
+func (r *FilterReporter) ReportBrokerStatus(s *store.BrokerStatuses) error {
+	return r.reporter.ReportBrokerStatus(s)
+}
+
+// ReportConsumerOffsets reports a snapshot of the consumer group offsets.
+func (r *FilterReporter) ReportConsumerOffsets(o *store.ConsumerOffsets) error {
+	if r.topics.Empty() && r.groups.Empty() {
+		return r.reporter.ReportConsumerOffsets(o)
+	}
+
+	filtered := store.ConsumerOffsets{}
+	for group, topics := range *o {
+		if !r.groups.Match(group) {
+			continue
+		}
+
+		for topic, partitions := range topics {
+			if !r.topics.Match(topic) {
+				continue
+			}
+
+			if _, ok := filtered[group]; !ok {
+				filtered[group] = map[string][]*store.ConsumerOffset{}
+			}
+			filtered[group][topic] = partitions
+		}
+	}
+
+	return r.reporter.ReportConsumerOffsets(&filtered)
+}
+
+// ReportOffsetRegressions reports newly detected consumer offset regressions.
+func (r *FilterReporter) ReportOffsetRegressions(o *store.OffsetRegressions) error {
+	if r.topics.Empty() && r.groups.Empty() {
+		return r.reporter.ReportOffsetRegressions(o)
+	}
+
+	filtered := store.OffsetRegressions{}
+	for _, regression := range *o {
+		if r.groups.Match(regression.Group) && r.topics.Match(regression.Topic) {
+			filtered = append(filtered, regression)
+		}
+	}
+
+	return r.reporter.ReportOffsetRegressions(&filtered)
+}
+
+// ReportMetrics reports a snapshot of the internal metrics.
+func (r *FilterReporter) ReportMetrics(m *metrics.Snapshot) error {
+	return r.reporter.ReportMetrics(m)
+}
+
+// patternFilter represents a set of include and exclude patterns.
+type patternFilter struct {
+	include []string
+	exclude []string
+}
+
+// Empty determines if the filter has no patterns.
+func (f patternFilter) Empty() bool {
+	return len(f.include) == 0 && len(f.exclude) == 0
+}
+
+// Match determines if the subject matches an include pattern, or there are
+// none, and does not match an exclude pattern.
+func (f patternFilter) Match(subject string) bool {
+	if len(f.include) > 0 && !matchAny(f.include, subject) {
+		return false
+	}
+
+	return !matchAny(f.exclude, subject)
+}
+
+// matchAny determines if the subject matches any of the patterns.
+func matchAny(patterns []string, subject string) bool {
+	for _, pattern := range patterns {
+		if glob.Glob(pattern, subject) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,58 @@
+package kage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIncludeTopics(t *testing.T) {
+	r := &FilterReporter{}
+
+	IncludeTopics("foo", "bar-*")(r)
+
+	assert.Equal(t, []string{"foo", "bar-*"}, r.topics.include)
+}
+
+func TestExcludeTopics(t *testing.T) {
+	r := &FilterReporter{}
+
+	ExcludeTopics("foo")(r)
+
+	assert.Equal(t, []string{"foo"}, r.topics.exclude)
+}
+
+func TestIncludeGroups(t *testing.T) {
+	r := &FilterReporter{}
+
+	IncludeGroups("billing-*")(r)
+
+	assert.Equal(t, []string{"billing-*"}, r.groups.include)
+}
+
+func TestExcludeGroups(t *testing.T) {
+	r := &FilterReporter{}
+
+	ExcludeGroups("test")(r)
+
+	assert.Equal(t, []string{"test"}, r.groups.exclude)
+}
+
+func TestPatternFilter_Match(t *testing.T) {
+	tests := []struct {
+		filter  patternFilter
+		subject string
+		want    bool
+	}{
+		{patternFilter{}, "foo", true},
+		{patternFilter{include: []string{"billing-*"}}, "billing-api", true},
+		{patternFilter{include: []string{"billing-*"}}, "orders", false},
+		{patternFilter{exclude: []string{"__*"}}, "__consumer_offsets", false},
+		{patternFilter{exclude: []string{"__*"}}, "orders", true},
+		{patternFilter{include: []string{"billing-*"}, exclude: []string{"*-test"}}, "billing-test", false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, tt.filter.Match(tt.subject), tt.subject)
+	}
+}
@@ -0,0 +1,84 @@
+package kage_test
+
+import (
+	"testing"
+
+	"github.com/msales/kage"
+	"github.com/msales/kage/metrics"
+	"github.com/msales/kage/store"
+	"github.com/msales/kage/testutil/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterReporter_PassesThroughWithoutPatterns(t *testing.T) {
+	bo := &store.BrokerOffsets{}
+	bm := &store.BrokerMetadata{}
+	bs := &store.BrokerStatuses{}
+	co := &store.ConsumerOffsets{}
+	or := &store.OffsetRegressions{}
+	m := &metrics.Snapshot{}
+
+	reporter := new(mocks.MockReporter)
+	reporter.On("ReportBrokerOffsets", bo).Return(nil)
+	reporter.On("ReportBrokerMetadata", bm).Return(nil)
+	reporter.On("ReportBrokerStatus", bs).Return(nil)
+	reporter.On("ReportConsumerOffsets", co).Return(nil)
+	reporter.On("ReportOffsetRegressions", or).Return(nil)
+	reporter.On("ReportMetrics", m).Return(nil)
+
+	r := kage.NewFilterReporter(reporter)
+
+	assert.NoError(t, r.ReportBrokerOffsets(bo))
+	assert.NoError(t, r.ReportBrokerMetadata(bm))
+	assert.NoError(t, r.ReportBrokerStatus(bs))
+	assert.NoError(t, r.ReportConsumerOffsets(co))
+	assert.NoError(t, r.ReportOffsetRegressions(or))
+	assert.NoError(t, r.ReportMetrics(m))
+	reporter.AssertExpectations(t)
+}
+
+func TestFilterReporter_FiltersTopics(t *testing.T) {
+	offset := &store.BrokerOffset{NewestOffset: 10}
+	metadata := &store.Metadata{Leader: 1}
+
+	reporter := new(mocks.MockReporter)
+	reporter.On("ReportBrokerOffsets", &store.BrokerOffsets{"orders": {offset}}).Return(nil)
+	reporter.On("ReportBrokerMetadata", &store.BrokerMetadata{"orders": {metadata}}).Return(nil)
+
+	r := kage.NewFilterReporter(reporter, kage.IncludeTopics("orders*"), kage.ExcludeTopics("*-test"))
+
+	assert.NoError(t, r.ReportBrokerOffsets(&store.BrokerOffsets{
+		"orders":      {offset},
+		"orders-test": {offset},
+		"payments":    {offset},
+	}))
+	assert.NoError(t, r.ReportBrokerMetadata(&store.BrokerMetadata{
+		"orders":   {metadata},
+		"payments": {metadata},
+	}))
+	reporter.AssertExpectations(t)
+}
+
+func TestFilterReporter_FiltersGroups(t *testing.T) {
+	offset := &store.ConsumerOffset{Offset: 10, Lag: 5}
+	rewind := &store.OffsetRegression{Group: "billing-api", Topic: "orders"}
+
+	reporter := new(mocks.MockReporter)
+	reporter.On("ReportConsumerOffsets", &store.ConsumerOffsets{
+		"billing-api": {"orders": {offset}},
+	}).Return(nil)
+	reporter.On("ReportOffsetRegressions", &store.OffsetRegressions{rewind}).Return(nil)
+
+	r := kage.NewFilterReporter(reporter, kage.IncludeGroups("billing-*"), kage.ExcludeTopics("__*"))
+
+	assert.NoError(t, r.ReportConsumerOffsets(&store.ConsumerOffsets{
+		"billing-api": {"orders": {offset}, "__internal": {offset}},
+		"billing-old": {"__internal": {offset}},
+		"shipping":    {"orders": {offset}},
+	}))
+	assert.NoError(t, r.ReportOffsetRegressions(&store.OffsetRegressions{
+		rewind,
+		{Group: "shipping", Topic: "orders"},
+	}))
+	reporter.AssertExpectations(t)
+}
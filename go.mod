@@ -1,26 +1,31 @@
 module github.com/msales/kage
 
+go 1.27.1
+
 require (
 	github.com/Shopify/sarama v1.19.0
+	github.com/go-zoo/bone v0.0.0-20180910124228-2270ec2a18cc
+	github.com/influxdata/influxdb v1.6.4
+	github.com/joho/godotenv v1.3.0
+	github.com/ryanuber/go-glob v0.0.0-20170128012129-256dc444b735
+	github.com/stretchr/testify v1.2.2
+	gopkg.in/inconshreveable/log15.v2 v2.0.0-20180818164646-67afb5ed74ec
+	gopkg.in/urfave/cli.v1 v1.20.0
+)
+
+require (
 	github.com/Shopify/toxiproxy v2.1.3+incompatible // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/eapache/go-resiliency v1.1.0 // indirect
 	github.com/eapache/go-xerial-snappy v0.0.0-20180814174437-776d5712da21 // indirect
 	github.com/eapache/queue v1.1.0 // indirect
 	github.com/go-stack/stack v1.8.0 // indirect
-	github.com/go-zoo/bone v0.0.0-20180910124228-2270ec2a18cc
 	github.com/golang/snappy v0.0.0-20180518054509-2e65f85255db // indirect
-	github.com/influxdata/influxdb v1.6.4
-	github.com/joho/godotenv v1.3.0
 	github.com/mattn/go-colorable v0.0.9 // indirect
 	github.com/mattn/go-isatty v0.0.4 // indirect
 	github.com/pierrec/lz4 v2.0.5+incompatible // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/rcrowley/go-metrics v0.0.0-20181016184325-3113b8401b8a // indirect
-	github.com/ryanuber/go-glob v0.0.0-20170128012129-256dc444b735
 	github.com/stretchr/objx v0.1.1 // indirect
-	github.com/stretchr/testify v1.2.2
 	golang.org/x/sys v0.0.0-20181023152157-44b849a8bc13 // indirect
-	gopkg.in/inconshreveable/log15.v2 v2.0.0-20180818164646-67afb5ed74ec
-	gopkg.in/urfave/cli.v1 v1.20.0
 )
@@ -0,0 +1,89 @@
+package kage
+
+import (
+	"time"
+
+	"github.com/msales/kage/metrics"
+	"github.com/msales/kage/store"
+)
+
+// Report types used to tag the reporter metrics.
+const (
+	reportBrokerOffsets     = "broker_offsets"
+	reportBrokerMetadata    = "broker_metadata"
+	reportBrokerStatus      = "broker_status"
+	reportConsumerOffsets   = "consumer_offsets"
+	reportOffsetRegressions = "offset_regressions"
+	reportMetrics           = "metrics"
+)
+
+// InstrumentedReporter records the write latency and failures of a Reporter.
+type InstrumentedReporter struct {
+	name     string
+	reporter Reporter
+	metrics  *metrics.Registry
+}
+
+// NewInstrumentedReporter creates and returns a new InstrumentedReporter.
+func NewInstrumentedReporter(name string, r Reporter, m *metrics.Registry) *InstrumentedReporter {
+	return &InstrumentedReporter{
+		name:     name,
+		reporter: r,
+		metrics:  m,
+	}
+}
+
+// ReportBrokerOffsets reports a snapshot of the broker offsets.
+func (r *InstrumentedReporter) ReportBrokerOffsets(o *store.BrokerOffsets) error {
+	return r.observe(reportBrokerOffsets, func() error {
+		return r.reporter.ReportBrokerOffsets(o)
+	})
+}
+
+// ReportBrokerMetadata reports a snapshot of the broker metadata.
+func (r *InstrumentedReporter) ReportBrokerMetadata(m *store.BrokerMetadata) error {
+	return r.observe(reportBrokerMetadata, func() error {
+		return r.reporter.ReportBrokerMetadata(m)
+	})
+}
+
+// ReportBrokerStatus reports the status of the brokers.
+func (r *InstrumentedReporter) ReportBrokerStatus(s *store.BrokerStatuses) error {
+	return r.observe(reportBrokerStatus, func() error {
+		return r.reporter.ReportBrokerStatus(s)
+	})
+}
+
+// ReportConsumerOffsets reports a snapshot of the consumer group offsets.
+func (r *InstrumentedReporter) ReportConsumerOffsets(o *store.ConsumerOffsets) error {
+	return r.observe(reportConsumerOffsets, func() error {
+		return r.reporter.ReportConsumerOffsets(o)
+	})
+}
+
+// ReportOffsetRegressions reports newly detected consumer offset regressions.
+func (r *InstrumentedReporter) ReportOffsetRegressions(o *store.OffsetRegressions) error {
+	return r.observe(reportOffsetRegressions, func() error {
+		return r.reporter.ReportOffsetRegressions(o)
+	})
+}
+
+// ReportMetrics reports a snapshot of the internal metrics.
+func (r *InstrumentedReporter) ReportMetrics(m *metrics.Snapshot) error {
+	return r.observe(reportMetrics, func() error {
+		return r.reporter.ReportMetrics(m)
+	})
+}
+
+// observe records the duration and failure of a report.
+func (r *InstrumentedReporter) observe(report string, fn func() error) error {
+	start := time.Now()
+	err := fn()
+	r.metrics.Timer("reporter.write.duration", "reporter", r.name, "report", report).UpdateSince(start)
+
+	if err != nil {
+		r.metrics.Counter("reporter.write.failures", "reporter", r.name, "report", report).Inc(1)
+	}
+
+	return err
+}
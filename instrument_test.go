@@ -0,0 +1,38 @@
+package kage_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/msales/kage"
+	"github.com/msales/kage/metrics"
+	"github.com/msales/kage/store"
+	"github.com/msales/kage/testutil/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstrumentedReporter(t *testing.T) {
+	bo := &store.BrokerOffsets{}
+	co := &store.ConsumerOffsets{}
+
+	reporter := new(mocks.MockReporter)
+	reporter.On("ReportBrokerOffsets", bo).Return(nil)
+	reporter.On("ReportConsumerOffsets", co).Return(errors.New("test error"))
+
+	r := metrics.NewRegistry()
+	ir := kage.NewInstrumentedReporter("test", reporter, r)
+
+	assert.NoError(t, ir.ReportBrokerOffsets(bo))
+	assert.Error(t, ir.ReportConsumerOffsets(co))
+
+	snapshot := r.Snapshot()
+
+	assert.Len(t, snapshot, 3)
+	assert.Equal(t, "reporter.write.duration", snapshot[0].Name)
+	assert.Equal(t, map[string]string{"reporter": "test", "report": "broker_offsets"}, snapshot[0].Tags)
+	assert.Equal(t, "reporter.write.duration", snapshot[1].Name)
+	assert.Equal(t, map[string]string{"reporter": "test", "report": "consumer_offsets"}, snapshot[1].Tags)
+	assert.Equal(t, "reporter.write.failures", snapshot[2].Name)
+	assert.Equal(t, map[string]float64{"count": 1}, snapshot[2].Values)
+	reporter.AssertExpectations(t)
+}
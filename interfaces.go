@@ -19,9 +19,21 @@ type Store interface {
 	// BrokerMetadata returns a snapshot of the current broker metadata.
 	BrokerMetadata() store.BrokerMetadata
 
+	// OffsetRegressions returns a snapshot of the consumer offset regression log.
+	OffsetRegressions() store.OffsetRegressions
+
+	// ClusterEvents returns a snapshot of the cluster event log.
+	ClusterEvents() store.ClusterEvents
+
 	// Channel get the offset channel.
 	Channel() chan interface{}
 
+	// Subscribe returns a channel receiving the changes committed to the store.
+	Subscribe() chan *store.Update
+
+	// Unsubscribe stops sending updates to the given channel.
+	Unsubscribe(ch chan *store.Update)
+
 	// Close gracefully stops the Store.
 	Close()
 }
@@ -0,0 +1,165 @@
+package kafka
+
+import (
+	"github.com/msales/kage/store"
+)
+
+// groupStateEmpty is the state of a consumer group without members.
+const groupStateEmpty = "Empty"
+
+// partitionState represents the state of a topic partition used to detect changes.
+type partitionState struct {
+	Leader int32
+	Isr    []int32
+}
+
+// topicState represents the partition states of a topic.
+type topicState []*partitionState
+
+// diffTopics sends the cluster events between the previous and current topic states to the store.
+func (m *Monitor) diffTopics(current map[string]topicState, ts int64) {
+	m.snapshotLock.Lock()
+	defer m.snapshotLock.Unlock()
+
+	previous := m.topics
+	m.topics = current
+
+	// The first snapshot only establishes the baseline
+	if previous == nil {
+		return
+	}
+
+	for topic, partitions := range current {
+		old, ok := previous[topic]
+		if !ok {
+			m.sendEvent(&store.ClusterEvent{
+				Type:      store.EventTopicCreated,
+				Topic:     topic,
+				New:       []int32{int32(len(partitions))},
+				Timestamp: ts,
+			})
+			continue
+		}
+
+		if len(partitions) > len(old) {
+			m.sendEvent(&store.ClusterEvent{
+				Type:      store.EventPartitionsAdded,
+				Topic:     topic,
+				Old:       []int32{int32(len(old))},
+				New:       []int32{int32(len(partitions))},
+				Timestamp: ts,
+			})
+		}
+
+		for i, partition := range partitions {
+			if i >= len(old) || partition == nil || old[i] == nil {
+				continue
+			}
+
+			if partition.Leader != old[i].Leader {
+				m.sendEvent(&store.ClusterEvent{
+					Type:      store.EventLeaderChanged,
+					Topic:     topic,
+					Partition: int32(i),
+					Old:       []int32{old[i].Leader},
+					New:       []int32{partition.Leader},
+					Timestamp: ts,
+				})
+			}
+
+			if hasMissing(old[i].Isr, partition.Isr) {
+				m.sendEvent(&store.ClusterEvent{
+					Type:      store.EventIsrShrink,
+					Topic:     topic,
+					Partition: int32(i),
+					Old:       old[i].Isr,
+					New:       partition.Isr,
+					Timestamp: ts,
+				})
+			}
+
+			if hasMissing(partition.Isr, old[i].Isr) {
+				m.sendEvent(&store.ClusterEvent{
+					Type:      store.EventIsrExpand,
+					Topic:     topic,
+					Partition: int32(i),
+					Old:       old[i].Isr,
+					New:       partition.Isr,
+					Timestamp: ts,
+				})
+			}
+		}
+	}
+
+	for topic, partitions := range previous {
+		if _, ok := current[topic]; ok {
+			continue
+		}
+
+		m.sendEvent(&store.ClusterEvent{
+			Type:      store.EventTopicDeleted,
+			Topic:     topic,
+			Old:       []int32{int32(len(partitions))},
+			Timestamp: ts,
+		})
+	}
+}
+
+// diffGroups sends the cluster events between the previous and current group states to the store.
+// Groups missing from the current states were deleted, which is not reported as emptied.
+func (m *Monitor) diffGroups(current map[string]string, ts int64) {
+	m.snapshotLock.Lock()
+	defer m.snapshotLock.Unlock()
+
+	previous := m.groups
+	m.groups = current
+
+	// The first snapshot only establishes the baseline
+	if previous == nil {
+		return
+	}
+
+	for group, state := range current {
+		old, ok := previous[group]
+		if !ok {
+			m.sendEvent(&store.ClusterEvent{
+				Type:      store.EventGroupAppeared,
+				Group:     group,
+				Timestamp: ts,
+			})
+			continue
+		}
+
+		if state == groupStateEmpty && old != groupStateEmpty {
+			m.sendEvent(&store.ClusterEvent{
+				Type:      store.EventGroupEmptied,
+				Group:     group,
+				Timestamp: ts,
+			})
+		}
+	}
+}
+
+// sendEvent sends a cluster event to the store.
+func (m *Monitor) sendEvent(e *store.ClusterEvent) {
+	m.stateCh <- e
+}
+
+// hasMissing determines if any of the replicas in a are missing from b.
+func hasMissing(a, b []int32) bool {
+	for _, x := range a {
+		found := false
+		for _, y := range b {
+			if x == y {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,32 @@
+package kafka
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/msales/kage/metrics"
+)
+
+// Collection phases.
+const (
+	phaseBrokerOffsets   = "broker_offsets"
+	phaseBrokerMetadata  = "broker_metadata"
+	phaseConsumerOffsets = "consumer_offsets"
+)
+
+// timePhase runs a collection phase, recording its duration.
+func (m *Monitor) timePhase(phase string, fn func()) {
+	start := time.Now()
+	fn()
+	m.metrics.Timer("monitor.collect.duration", "phase", phase).UpdateSince(start)
+}
+
+// phaseError counts an error in a collection phase.
+func (m *Monitor) phaseError(phase string) {
+	m.metrics.Counter("monitor.collect.errors", "phase", phase).Inc(1)
+}
+
+// requestTimer returns the latency timer of a broker request.
+func (m *Monitor) requestTimer(brokerID int32, request string) *metrics.Timer {
+	return m.metrics.Timer("monitor.request.duration", "broker", fmt.Sprint(brokerID), "request", request)
+}
@@ -0,0 +1,43 @@
+package kafka
+
+import (
+	"testing"
+
+	"github.com/msales/kage/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMonitor_timePhase(t *testing.T) {
+	c := &Monitor{metrics: metrics.NewRegistry()}
+
+	called := false
+	c.timePhase(phaseBrokerOffsets, func() { called = true })
+	c.phaseError(phaseBrokerOffsets)
+
+	snapshot := c.metrics.Snapshot()
+
+	assert.True(t, called)
+	assert.Len(t, snapshot, 2)
+	assert.Equal(t, "monitor.collect.duration", snapshot[0].Name)
+	assert.Equal(t, map[string]string{"phase": phaseBrokerOffsets}, snapshot[0].Tags)
+	assert.Equal(t, float64(1), snapshot[0].Values["count"])
+	assert.Equal(t, "monitor.collect.errors", snapshot[1].Name)
+	assert.Equal(t, float64(1), snapshot[1].Values["count"])
+}
+
+func TestMonitor_requestTimer(t *testing.T) {
+	c := &Monitor{metrics: metrics.NewRegistry()}
+
+	timer := c.requestTimer(1, "metadata")
+
+	assert.Equal(t, timer, c.requestTimer(1, "metadata"))
+	assert.Equal(t, map[string]string{"broker": "1", "request": "metadata"}, c.metrics.Snapshot()[0].Tags)
+}
+
+func TestMonitor_NilMetrics(t *testing.T) {
+	c := &Monitor{}
+
+	c.timePhase(phaseBrokerMetadata, func() {})
+	c.phaseError(phaseBrokerMetadata)
+	c.requestTimer(1, "metadata").Update(0)
+}
@@ -6,15 +6,18 @@ import (
 	"time"
 
 	"github.com/Shopify/sarama"
+	"github.com/msales/kage/metrics"
 	"github.com/msales/kage/store"
+	"github.com/msales/kage/utils"
 	"github.com/ryanuber/go-glob"
 	"gopkg.in/inconshreveable/log15.v2"
 )
 
 // Broker represents a Kafka Broker.
 type Broker struct {
-	ID        int32
-	Connected bool
+	ID         int32
+	Connected  bool
+	Controller bool
 }
 
 // Monitor represents a Kafka cluster connection.
@@ -28,6 +31,14 @@ type Monitor struct {
 	ignoreTopics []string
 	ignoreGroups []string
 
+	noInitialCollect bool
+
+	snapshotLock sync.Mutex
+	topics       map[string]topicState
+	groups       map[string]string
+
+	metrics *metrics.Registry
+
 	log log15.Logger
 }
 
@@ -56,29 +67,51 @@ func New(opts ...MonitorFunc) (*Monitor, error) {
 	}()
 
 	// Collect initial information
-	go monitor.Collect()
+	if !monitor.noInitialCollect {
+		go monitor.Collect()
+	}
 
 	return monitor, nil
 }
 
 // Brokers returns a list of Kafka brokers.
 func (m *Monitor) Brokers() []Broker {
+	controllerID := int32(-1)
+	if controller, err := m.client.Controller(); err == nil {
+		controllerID = controller.ID()
+	}
+
 	brokers := []Broker{}
 	for _, b := range m.client.Brokers() {
 		connected, _ := b.Connected()
 		brokers = append(brokers, Broker{
-			ID:        b.ID(),
-			Connected: connected,
+			ID:         b.ID(),
+			Connected:  connected,
+			Controller: b.ID() == controllerID,
 		})
 	}
 	return brokers
 }
 
+// ConnectBrokers opens a connection to every broker and waits until
+// each connection is established or has failed.
+func (m *Monitor) ConnectBrokers() {
+	brokers := m.client.Brokers()
+	for _, b := range brokers {
+		b.Open(m.client.Config())
+	}
+
+	// Connected blocks while the broker is connecting.
+	for _, b := range brokers {
+		b.Connected()
+	}
+}
+
 // Collect collects the state of Kafka.
 func (m *Monitor) Collect() {
-	m.getBrokerOffsets()
-	m.getBrokerMetadata()
-	m.getConsumerOffsets()
+	m.timePhase(phaseBrokerOffsets, m.getBrokerOffsets)
+	m.timePhase(phaseBrokerMetadata, m.getBrokerMetadata)
+	m.timePhase(phaseConsumerOffsets, m.getConsumerOffsets)
 }
 
 // IsHealthy checks the health of the Kafka cluster.
@@ -140,6 +173,7 @@ func (m *Monitor) getBrokerOffsets() {
 			broker, err := m.client.Leader(topic, int32(i))
 			if err != nil {
 				m.log.Error(fmt.Sprintf("topic leader error on %s:%v: %v", topic, int32(i), err))
+				m.phaseError(phaseBrokerOffsets)
 				return
 			}
 
@@ -159,16 +193,19 @@ func (m *Monitor) getBrokerOffsets() {
 	getBrokerOffsets := func(brokerID int32, position int64, request *sarama.OffsetRequest) {
 		defer wg.Done()
 
+		start := time.Now()
 		response, err := brokers[brokerID].GetAvailableOffsets(request)
+		m.requestTimer(brokerID, "offsets").UpdateSince(start)
 		if err != nil {
 			m.log.Error(fmt.Sprintf("cannot fetch offsets from broker %v: %v", brokerID, err))
+			m.phaseError(phaseBrokerOffsets)
 
 			brokers[brokerID].Close()
 
 			return
 		}
 
-		ts := time.Now().Unix() * 1000
+		ts := utils.Millis(time.Now())
 		for topic, partitions := range response.Blocks {
 			for partition, offsetResp := range partitions {
 				if offsetResp.Err != sarama.ErrNoError {
@@ -181,6 +218,7 @@ func (m *Monitor) getBrokerOffsets() {
 					}
 
 					m.log.Warn(fmt.Sprintf("error in OffsetResponse for %s:%v from broker %v: %s", topic, partition, brokerID, offsetResp.Err.Error()))
+					m.phaseError(phaseBrokerOffsets)
 					continue
 				}
 
@@ -222,29 +260,60 @@ func (m *Monitor) getBrokerMetadata() {
 
 	if broker == nil {
 		m.log.Error("monitor: no connected brokers found to collect metadata")
+		m.phaseError(phaseBrokerMetadata)
 		return
 	}
 
+	start := time.Now()
 	response, err := broker.GetMetadata(&sarama.MetadataRequest{})
+	m.requestTimer(broker.ID(), "metadata").UpdateSince(start)
 	if err != nil {
 		m.log.Error(fmt.Sprintf("monitor: cannot get metadata: %v", err))
+		m.phaseError(phaseBrokerMetadata)
 		return
 	}
 
-	ts := time.Now().Unix() * 1000
+	ts := utils.Millis(time.Now())
+	topics := make(map[string]topicState)
 	for _, topic := range response.Topics {
 		if containsString(m.ignoreTopics, topic.Name) {
 			continue
 		}
 		if topic.Err != sarama.ErrNoError {
 			m.log.Error(fmt.Sprintf("monitor: cannot get topic metadata %s: %v", topic.Name, topic.Err.Error()))
+			m.phaseError(phaseBrokerMetadata)
+
+			// Keep the last known state to avoid reporting the topic as deleted
+			m.snapshotLock.Lock()
+			if state, ok := m.topics[topic.Name]; ok {
+				topics[topic.Name] = state
+			}
+			m.snapshotLock.Unlock()
 			continue
 		}
 
 		partitionCount := len(topic.Partitions)
+		topics[topic.Name] = make(topicState, partitionCount)
 		for _, partition := range topic.Partitions {
-			if partition.Err != sarama.ErrNoError {
+			if partition.ID >= 0 && int(partition.ID) < partitionCount {
+				topics[topic.Name][partition.ID] = &partitionState{Leader: partition.Leader, Isr: partition.Isr}
+			}
+
+			leader := partition.Leader
+			switch partition.Err {
+			case sarama.ErrNoError:
+
+			case sarama.ErrLeaderNotAvailable, sarama.ErrReplicaNotAvailable:
+				// The partition is offline or under replicated, which is
+				// state to report rather than a collection error.
+				m.log.Warn(fmt.Sprintf("monitor: topic partition metadata %s %d: %v", topic.Name, partition.ID, partition.Err.Error()))
+				if partition.Err == sarama.ErrLeaderNotAvailable {
+					leader = -1
+				}
+
+			default:
 				m.log.Error(fmt.Sprintf("monitor: cannot get topic partition metadata %s %d: %v", topic.Name, partition.ID, partition.Err.Error()))
+				m.phaseError(phaseBrokerMetadata)
 				continue
 			}
 
@@ -252,13 +321,15 @@ func (m *Monitor) getBrokerMetadata() {
 				Topic:               topic.Name,
 				Partition:           partition.ID,
 				TopicPartitionCount: partitionCount,
-				Leader:              partition.Leader,
+				Leader:              leader,
 				Replicas:            partition.Replicas,
 				Isr:                 partition.Isr,
 				Timestamp:           ts,
 			}
 		}
 	}
+
+	m.diffTopics(topics, ts)
 }
 
 // getConsumerOffsets gets all the consumer offsets and send them to the store.
@@ -267,23 +338,34 @@ func (m *Monitor) getConsumerOffsets() {
 	requests := make(map[int32]map[string]*sarama.OffsetFetchRequest)
 	coordinators := make(map[int32]*sarama.Broker)
 
+	// Whether every group was listed with its coordinator
+	listed := true
+
 	brokers := m.client.Brokers()
 	for _, broker := range brokers {
 		if ok, err := broker.Connected(); !ok {
 			if err != nil {
 				m.log.Error(fmt.Sprintf("monitor: failed to connect to broker broker %v: %v", broker.ID(), err))
+				m.phaseError(phaseConsumerOffsets)
+				listed = false
 				continue
 			}
 
 			if err := broker.Open(m.client.Config()); err != nil {
 				m.log.Error(fmt.Sprintf("monitor: failed to connect to broker broker %v: %v", broker.ID(), err))
+				m.phaseError(phaseConsumerOffsets)
+				listed = false
 				continue
 			}
 		}
 
+		start := time.Now()
 		groups, err := broker.ListGroups(&sarama.ListGroupsRequest{})
+		m.requestTimer(broker.ID(), "list_groups").UpdateSince(start)
 		if err != nil {
 			m.log.Error(fmt.Sprintf("monitor: cannot fetch consumer groups on broker %v: %v", broker.ID(), err))
+			m.phaseError(phaseConsumerOffsets)
+			listed = false
 			continue
 		}
 
@@ -295,6 +377,8 @@ func (m *Monitor) getConsumerOffsets() {
 			coordinator, err := m.client.Coordinator(group)
 			if err != nil {
 				m.log.Error(fmt.Sprintf("monitor: cannot fetch co-ordinator for group %s: %v", group, err))
+				m.phaseError(phaseConsumerOffsets)
+				listed = false
 				continue
 			}
 
@@ -321,18 +405,22 @@ func (m *Monitor) getConsumerOffsets() {
 
 		coordinator := coordinators[brokerID]
 
+		start := time.Now()
 		offsets, err := coordinator.FetchOffset(request)
+		m.requestTimer(brokerID, "fetch_offset").UpdateSince(start)
 		if err != nil {
 			m.log.Error(fmt.Sprintf("monitor: cannot get group topic offsets %v: %v", brokerID, err))
+			m.phaseError(phaseConsumerOffsets)
 
 			return
 		}
 
-		ts := time.Now().Unix() * 1000
+		ts := utils.Millis(time.Now())
 		for topic, partitions := range offsets.Blocks {
 			for partition, block := range partitions {
 				if block.Err != sarama.ErrNoError {
 					m.log.Error(fmt.Sprintf("monitor: cannot get group topic offsets %v: %v", brokerID, block.Err.Error()))
+					m.phaseError(phaseConsumerOffsets)
 					continue
 				}
 
@@ -363,6 +451,44 @@ func (m *Monitor) getConsumerOffsets() {
 	}
 
 	wg.Wait()
+
+	// The groups that could not be listed would look deleted, so the group
+	// states are only compared when every group was listed.
+	if listed {
+		m.getGroupStates(coordinators, requests)
+	}
+}
+
+// getGroupStates gets the state of the consumer groups and sends the changes to the store.
+func (m *Monitor) getGroupStates(coordinators map[int32]*sarama.Broker, requests map[int32]map[string]*sarama.OffsetFetchRequest) {
+	states := make(map[string]string)
+	for brokerID, groups := range requests {
+		request := &sarama.DescribeGroupsRequest{}
+		for group := range groups {
+			request.AddGroup(group)
+		}
+
+		start := time.Now()
+		response, err := coordinators[brokerID].DescribeGroups(request)
+		m.requestTimer(brokerID, "describe_groups").UpdateSince(start)
+		if err != nil {
+			m.log.Error(fmt.Sprintf("monitor: cannot describe consumer groups on broker %v: %v", brokerID, err))
+			m.phaseError(phaseConsumerOffsets)
+			return
+		}
+
+		for _, group := range response.Groups {
+			if group.Err != sarama.ErrNoError {
+				m.log.Error(fmt.Sprintf("monitor: cannot describe consumer group %s: %v", group.GroupId, group.Err.Error()))
+				m.phaseError(phaseConsumerOffsets)
+				return
+			}
+
+			states[group.GroupId] = group.State
+		}
+	}
+
+	m.diffGroups(states, utils.Millis(time.Now()))
 }
 
 // containsString determines if the string matches any of the provided patterns.
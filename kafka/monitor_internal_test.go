@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/Shopify/sarama"
+	"github.com/msales/kage/store"
 	"github.com/msales/kage/testutil"
 	"github.com/stretchr/testify/assert"
 )
@@ -31,6 +32,63 @@ func TestMonitor_Brokers(t *testing.T) {
 	broker1.Close()
 }
 
+func TestMonitor_BrokersController(t *testing.T) {
+	broker0 := sarama.NewMockBroker(t, 0)
+	broker1 := sarama.NewMockBroker(t, 1)
+	broker0.SetHandlerByMap(map[string]sarama.MockResponse{
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(broker0.Addr(), broker0.BrokerID()).
+			SetBroker(broker1.Addr(), broker1.BrokerID()).
+			SetController(broker1.BrokerID()),
+	})
+
+	config := sarama.NewConfig()
+	config.Version = sarama.V0_10_1_0
+	kafka, err := sarama.NewClient([]string{broker0.Addr()}, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &Monitor{client: kafka}
+
+	controllers := map[int32]bool{}
+	for _, b := range c.Brokers() {
+		controllers[b.ID] = b.Controller
+	}
+	assert.Equal(t, map[int32]bool{0: false, 1: true}, controllers)
+
+	broker0.Close()
+	broker1.Close()
+}
+
+func TestMonitor_ConnectBrokers(t *testing.T) {
+	broker0 := sarama.NewMockBroker(t, 0)
+	broker1 := sarama.NewMockBroker(t, 1)
+	broker0.SetHandlerByMap(map[string]sarama.MockResponse{
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(broker0.Addr(), broker0.BrokerID()).
+			SetBroker(broker1.Addr(), broker1.BrokerID()),
+	})
+
+	kafka, err := sarama.NewClient([]string{broker0.Addr()}, sarama.NewConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &Monitor{client: kafka}
+
+	c.ConnectBrokers()
+
+	connected := map[int32]bool{}
+	for _, b := range c.Brokers() {
+		connected[b.ID] = b.Connected
+	}
+	assert.Equal(t, map[int32]bool{0: true, 1: true}, connected)
+
+	broker0.Close()
+	broker1.Close()
+}
+
 func TestMonitor_RefreshMetadata(t *testing.T) {
 	broker := sarama.NewMockBroker(t, 0)
 	broker.SetHandlerByMap(map[string]sarama.MockResponse{
@@ -137,6 +195,39 @@ func TestMonitor_getBrokerMetadata(t *testing.T) {
 	broker.Close()
 }
 
+func TestMonitor_getBrokerMetadataOfflinePartition(t *testing.T) {
+	broker := sarama.NewMockBroker(t, 0)
+	metadata := &sarama.MetadataResponse{}
+	metadata.AddBroker(broker.Addr(), broker.BrokerID())
+	metadata.AddTopicPartition("foo", 0, -1, []int32{broker.BrokerID()}, []int32{}, sarama.ErrLeaderNotAvailable)
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"MetadataRequest": sarama.NewMockWrapper(metadata),
+	})
+
+	config := sarama.NewConfig()
+	config.Metadata.Retry.Max = 0
+	kafka, err := sarama.NewClient([]string{broker.Addr()}, config)
+	assert.NoError(t, err)
+	for _, b := range kafka.Brokers() {
+		b.Open(kafka.Config())
+	}
+
+	c := &Monitor{
+		client:  kafka,
+		stateCh: make(chan interface{}, 100),
+		log:     testutil.Logger,
+	}
+
+	c.getBrokerMetadata()
+
+	assert.Len(t, c.stateCh, 1)
+	state := (<-c.stateCh).(*store.BrokerPartitionMetadata)
+	assert.Equal(t, "foo", state.Topic)
+	assert.Equal(t, int32(-1), state.Leader)
+
+	broker.Close()
+}
+
 func TestMonitor_getConsumerOffsets(t *testing.T) {
 	broker := sarama.NewMockBroker(t, 0)
 	broker.SetHandlerByMap(map[string]sarama.MockResponse{
@@ -156,6 +247,12 @@ func TestMonitor_getConsumerOffsets(t *testing.T) {
 		"OffsetFetchRequest": sarama.NewMockOffsetFetchResponse(t).
 			SetOffset("test", "foo", 0, 123, "", sarama.ErrNoError).
 			SetOffset("unread", "foo", 0, -1, "", sarama.ErrNoError),
+		"DescribeGroupsRequest": sarama.NewMockWrapper(&sarama.DescribeGroupsResponse{
+			Groups: []*sarama.GroupDescription{
+				{GroupId: "test", State: "Stable"},
+				{GroupId: "unread", State: "Empty"},
+			},
+		}),
 	})
 
 	conf := sarama.NewConfig()
@@ -176,3 +273,101 @@ func TestMonitor_getConsumerOffsets(t *testing.T) {
 
 	broker.Close()
 }
+
+func TestMonitor_getConsumerOffsetsKeepsGroupsOnListingError(t *testing.T) {
+	broker := sarama.NewMockBroker(t, 0)
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(broker.Addr(), broker.BrokerID()).
+			SetLeader("foo", 0, broker.BrokerID()),
+		"ListGroupsRequest": sarama.NewMockWrapper(&sarama.ListGroupsResponse{
+			Err:    sarama.ErrNoError,
+			Groups: map[string]string{"test": "consumer", "nocoord": "consumer"},
+		}),
+		"FindCoordinatorRequest": sarama.NewMockFindCoordinatorResponse(t).
+			SetCoordinator(sarama.CoordinatorGroup, "test", broker).
+			SetError(sarama.CoordinatorGroup, "nocoord", sarama.ErrGroupAuthorizationFailed),
+		"OffsetFetchRequest": sarama.NewMockOffsetFetchResponse(t).
+			SetOffset("test", "foo", 0, 123, "", sarama.ErrNoError),
+		"DescribeGroupsRequest": sarama.NewMockWrapper(&sarama.DescribeGroupsResponse{
+			Groups: []*sarama.GroupDescription{{GroupId: "test", State: "Stable"}},
+		}),
+	})
+
+	conf := sarama.NewConfig()
+	conf.Version = sarama.V0_10_1_0
+	conf.Metadata.Retry.Max = 0
+	kafka, err := sarama.NewClient([]string{broker.Addr()}, conf)
+	assert.NoError(t, err)
+
+	groups := map[string]string{"test": "Stable", "nocoord": "Stable"}
+	c := &Monitor{
+		client:  kafka,
+		stateCh: make(chan interface{}, 100),
+		log:     testutil.Logger,
+		groups:  groups,
+	}
+
+	c.getConsumerOffsets()
+
+	assert.Len(t, c.stateCh, 1)
+	assert.Equal(t, groups, c.groups)
+
+	broker.Close()
+}
+
+func TestMonitor_diffTopics(t *testing.T) {
+	c := &Monitor{
+		stateCh: make(chan interface{}, 100),
+	}
+
+	c.diffTopics(map[string]topicState{
+		"foo":     {{Leader: 1, Isr: []int32{1, 2}}},
+		"deleted": {{Leader: 1, Isr: []int32{1}}},
+	}, 0)
+
+	assert.Len(t, c.stateCh, 0)
+
+	c.diffTopics(map[string]topicState{
+		"foo":     {{Leader: 2, Isr: []int32{2, 3}}, {Leader: 1, Isr: []int32{1}}},
+		"created": {{Leader: 1, Isr: []int32{1}}},
+	}, 0)
+
+	types := map[string]int{}
+	for len(c.stateCh) > 0 {
+		e := (<-c.stateCh).(*store.ClusterEvent)
+		types[e.Type]++
+	}
+
+	assert.Equal(t, map[string]int{
+		store.EventTopicCreated:    1,
+		store.EventTopicDeleted:    1,
+		store.EventPartitionsAdded: 1,
+		store.EventLeaderChanged:   1,
+		store.EventIsrShrink:       1,
+		store.EventIsrExpand:       1,
+	}, types)
+}
+
+func TestMonitor_diffGroups(t *testing.T) {
+	c := &Monitor{
+		stateCh: make(chan interface{}, 100),
+	}
+
+	c.diffGroups(map[string]string{"foo": "Stable", "bar": "Stable", "baz": "Empty"}, 0)
+
+	assert.Len(t, c.stateCh, 0)
+
+	c.diffGroups(map[string]string{"foo": "Empty", "new": "Stable"}, 0)
+
+	events := map[string]string{}
+	for len(c.stateCh) > 0 {
+		e := (<-c.stateCh).(*store.ClusterEvent)
+		events[e.Group] = e.Type
+	}
+
+	assert.Equal(t, map[string]string{
+		"foo": store.EventGroupEmptied,
+		"new": store.EventGroupAppeared,
+	}, events)
+}
@@ -1,6 +1,7 @@
 package kafka
 
 import (
+	"github.com/msales/kage/metrics"
 	"gopkg.in/inconshreveable/log15.v2"
 )
 
@@ -41,3 +42,18 @@ func StateChannel(ch chan interface{}) MonitorFunc {
 		c.stateCh = ch
 	}
 }
+
+// Metrics configures the internal metrics registry on the Monitor.
+func Metrics(r *metrics.Registry) MonitorFunc {
+	return func(c *Monitor) {
+		c.metrics = r
+	}
+}
+
+// NoInitialCollect configures the Monitor not to collect the state in the
+// background when it is created.
+func NoInitialCollect() MonitorFunc {
+	return func(c *Monitor) {
+		c.noInitialCollect = true
+	}
+}
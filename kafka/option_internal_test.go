@@ -3,6 +3,7 @@ package kafka
 import (
 	"testing"
 
+	"github.com/msales/kage/metrics"
 	"github.com/stretchr/testify/assert"
 	"gopkg.in/inconshreveable/log15.v2"
 )
@@ -51,3 +52,20 @@ func TestOffsetChannel(t *testing.T) {
 
 	assert.Equal(t, ch, c.stateCh)
 }
+
+func TestMetrics(t *testing.T) {
+	r := metrics.NewRegistry()
+	c := &Monitor{}
+
+	Metrics(r)(c)
+
+	assert.Equal(t, r, c.metrics)
+}
+
+func TestNoInitialCollect(t *testing.T) {
+	c := &Monitor{}
+
+	NoInitialCollect()(c)
+
+	assert.True(t, c.noInitialCollect)
+}
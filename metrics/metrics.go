@@ -0,0 +1,306 @@
+package metrics
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Metric types.
+const (
+	TypeCounter = "counter"
+	TypeGauge   = "gauge"
+	TypeTimer   = "timer"
+)
+
+// Snapshot represents the state of all internal metrics.
+type Snapshot []*Metric
+
+// Metric represents the state of an internal metric. Timer values are in milliseconds.
+type Metric struct {
+	Name   string
+	Type   string
+	Tags   map[string]string
+	Values map[string]float64
+}
+
+// Registry represents a set of internal metrics. A nil Registry
+// discards all measurements.
+type Registry struct {
+	lock    sync.Mutex
+	metrics map[string]metric
+}
+
+// NewRegistry creates and returns a new Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		metrics: make(map[string]metric),
+	}
+}
+
+// Counter returns the counter with the given name and tag key value pairs.
+func (r *Registry) Counter(name string, tags ...string) *Counter {
+	if r == nil {
+		return nil
+	}
+
+	return r.getOrAdd(name, tags, func(t map[string]string) metric {
+		return &Counter{name: name, tags: t}
+	}).(*Counter)
+}
+
+// Gauge returns the gauge with the given name and tag key value pairs.
+func (r *Registry) Gauge(name string, tags ...string) *Gauge {
+	if r == nil {
+		return nil
+	}
+
+	return r.getOrAdd(name, tags, func(t map[string]string) metric {
+		return &Gauge{name: name, tags: t}
+	}).(*Gauge)
+}
+
+// GaugeFunc registers a gauge whose value is read from fn when a snapshot is taken.
+func (r *Registry) GaugeFunc(name string, fn func() float64, tags ...string) {
+	if r == nil {
+		return
+	}
+
+	r.getOrAdd(name, tags, func(t map[string]string) metric {
+		return &gaugeFunc{name: name, tags: t, fn: fn}
+	})
+}
+
+// Timer returns the timer with the given name and tag key value pairs.
+func (r *Registry) Timer(name string, tags ...string) *Timer {
+	if r == nil {
+		return nil
+	}
+
+	return r.getOrAdd(name, tags, func(t map[string]string) metric {
+		return &Timer{name: name, tags: t}
+	}).(*Timer)
+}
+
+// Snapshot returns a snapshot of all metrics, sorted by name and tags.
+func (r *Registry) Snapshot() Snapshot {
+	if r == nil {
+		return Snapshot{}
+	}
+
+	r.lock.Lock()
+	keys := make([]string, 0, len(r.metrics))
+	for key := range r.metrics {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	metrics := make([]metric, len(keys))
+	for i, key := range keys {
+		metrics[i] = r.metrics[key]
+	}
+	r.lock.Unlock()
+
+	snapshot := make(Snapshot, len(metrics))
+	for i, m := range metrics {
+		snapshot[i] = m.snapshot()
+	}
+
+	return snapshot
+}
+
+func (r *Registry) getOrAdd(name string, tags []string, fn func(map[string]string) metric) metric {
+	t := tagMap(tags)
+	key := metricKey(name, t)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if m, ok := r.metrics[key]; ok {
+		return m
+	}
+
+	m := fn(t)
+	r.metrics[key] = m
+
+	return m
+}
+
+type metric interface {
+	snapshot() *Metric
+}
+
+// Counter represents a monotonically increasing count.
+type Counter struct {
+	name string
+	tags map[string]string
+
+	lock  sync.Mutex
+	count int64
+}
+
+// Inc increments the counter by n.
+func (c *Counter) Inc(n int64) {
+	if c == nil {
+		return
+	}
+
+	c.lock.Lock()
+	c.count += n
+	c.lock.Unlock()
+}
+
+func (c *Counter) snapshot() *Metric {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return &Metric{
+		Name:   c.name,
+		Type:   TypeCounter,
+		Tags:   copyTags(c.tags),
+		Values: map[string]float64{"count": float64(c.count)},
+	}
+}
+
+// Gauge represents a value that can go up and down.
+type Gauge struct {
+	name string
+	tags map[string]string
+
+	lock  sync.Mutex
+	value float64
+}
+
+// Set sets the value of the gauge.
+func (g *Gauge) Set(v float64) {
+	if g == nil {
+		return
+	}
+
+	g.lock.Lock()
+	g.value = v
+	g.lock.Unlock()
+}
+
+func (g *Gauge) snapshot() *Metric {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	return &Metric{
+		Name:   g.name,
+		Type:   TypeGauge,
+		Tags:   copyTags(g.tags),
+		Values: map[string]float64{"value": g.value},
+	}
+}
+
+type gaugeFunc struct {
+	name string
+	tags map[string]string
+	fn   func() float64
+}
+
+func (g *gaugeFunc) snapshot() *Metric {
+	return &Metric{
+		Name:   g.name,
+		Type:   TypeGauge,
+		Tags:   copyTags(g.tags),
+		Values: map[string]float64{"value": g.fn()},
+	}
+}
+
+// Timer represents the distribution of a duration.
+type Timer struct {
+	name string
+	tags map[string]string
+
+	lock  sync.Mutex
+	count int64
+	sum   time.Duration
+	last  time.Duration
+	max   time.Duration
+}
+
+// Update records a duration.
+func (t *Timer) Update(d time.Duration) {
+	if t == nil {
+		return
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.count++
+	t.sum += d
+	t.last = d
+	if d > t.max {
+		t.max = d
+	}
+}
+
+// UpdateSince records the duration since the given start time.
+func (t *Timer) UpdateSince(start time.Time) {
+	t.Update(time.Since(start))
+}
+
+func (t *Timer) snapshot() *Metric {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	mean := time.Duration(0)
+	if t.count > 0 {
+		mean = t.sum / time.Duration(t.count)
+	}
+
+	return &Metric{
+		Name: t.name,
+		Type: TypeTimer,
+		Tags: copyTags(t.tags),
+		Values: map[string]float64{
+			"count": float64(t.count),
+			"last":  milliseconds(t.last),
+			"mean":  milliseconds(mean),
+			"max":   milliseconds(t.max),
+		},
+	}
+}
+
+// tagMap converts tag key value pairs into a map.
+func tagMap(tags []string) map[string]string {
+	m := make(map[string]string, len(tags)/2)
+	for i := 0; i+1 < len(tags); i += 2 {
+		m[tags[i]] = tags[i+1]
+	}
+
+	return m
+}
+
+// metricKey returns the unique key of a metric.
+func metricKey(name string, tags map[string]string) string {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := []string{name}
+	for _, k := range keys {
+		parts = append(parts, k+"="+tags[k])
+	}
+
+	return strings.Join(parts, ",")
+}
+
+func copyTags(tags map[string]string) map[string]string {
+	c := make(map[string]string, len(tags))
+	for k, v := range tags {
+		c[k] = v
+	}
+
+	return c
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,74 @@
+package metrics_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/msales/kage/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry_Counter(t *testing.T) {
+	r := metrics.NewRegistry()
+
+	r.Counter("errors", "phase", "test").Inc(1)
+	r.Counter("errors", "phase", "test").Inc(2)
+	r.Counter("errors", "phase", "other").Inc(1)
+
+	snapshot := r.Snapshot()
+
+	assert.Len(t, snapshot, 2)
+	assert.Equal(t, &metrics.Metric{
+		Name:   "errors",
+		Type:   metrics.TypeCounter,
+		Tags:   map[string]string{"phase": "other"},
+		Values: map[string]float64{"count": 1},
+	}, snapshot[0])
+	assert.Equal(t, &metrics.Metric{
+		Name:   "errors",
+		Type:   metrics.TypeCounter,
+		Tags:   map[string]string{"phase": "test"},
+		Values: map[string]float64{"count": 3},
+	}, snapshot[1])
+}
+
+func TestRegistry_Gauge(t *testing.T) {
+	r := metrics.NewRegistry()
+
+	r.Gauge("depth").Set(5)
+	r.GaugeFunc("capacity", func() float64 { return 10 })
+
+	snapshot := r.Snapshot()
+
+	assert.Len(t, snapshot, 2)
+	assert.Equal(t, "capacity", snapshot[0].Name)
+	assert.Equal(t, map[string]float64{"value": 10}, snapshot[0].Values)
+	assert.Equal(t, "depth", snapshot[1].Name)
+	assert.Equal(t, map[string]float64{"value": 5}, snapshot[1].Values)
+}
+
+func TestRegistry_Timer(t *testing.T) {
+	r := metrics.NewRegistry()
+
+	timer := r.Timer("duration")
+	timer.Update(2 * time.Millisecond)
+	timer.Update(4 * time.Millisecond)
+	timer.Update(3 * time.Millisecond)
+
+	snapshot := r.Snapshot()
+
+	assert.Len(t, snapshot, 1)
+	assert.Equal(t, metrics.TypeTimer, snapshot[0].Type)
+	assert.Equal(t, map[string]float64{"count": 3, "last": 3, "mean": 3, "max": 4}, snapshot[0].Values)
+}
+
+func TestRegistry_Nil(t *testing.T) {
+	var r *metrics.Registry
+
+	r.Counter("errors").Inc(1)
+	r.Gauge("depth").Set(1)
+	r.GaugeFunc("capacity", func() float64 { return 1 })
+	r.Timer("duration").UpdateSince(time.Now())
+
+	assert.Len(t, r.Snapshot(), 0)
+}
@@ -0,0 +1,185 @@
+package reporter
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/msales/kage/store"
+)
+
+// AggregationLevel represents the level partition values are aggregated to.
+type AggregationLevel string
+
+// Aggregation levels.
+const (
+	// AggregatePartition reports every partition.
+	AggregatePartition AggregationLevel = "partition"
+	// AggregateTopic reports the totals of every topic.
+	AggregateTopic AggregationLevel = "topic"
+	// AggregateGroup reports the totals of every consumer group. Broker
+	// values have no group, and are reported per topic.
+	AggregateGroup AggregationLevel = "group"
+)
+
+// ParseAggregation parses an aggregation level.
+func ParseAggregation(s string) (AggregationLevel, error) {
+	switch a := AggregationLevel(s); a {
+	case AggregatePartition, AggregateTopic, AggregateGroup:
+		return a, nil
+	default:
+		return "", fmt.Errorf("unknown aggregation \"%s\"", s)
+	}
+}
+
+// brokerAggregate represents the broker offsets of a topic.
+type brokerAggregate struct {
+	Topic      string
+	Partitions int
+	Available  int64
+	Timestamp  int64
+}
+
+// aggregateBrokerOffsets totals the broker offsets per topic, sorted by topic.
+func aggregateBrokerOffsets(o *store.BrokerOffsets) []*brokerAggregate {
+	aggs := []*brokerAggregate{}
+	for topic, partitions := range *o {
+		agg := &brokerAggregate{Topic: topic}
+		for _, offset := range partitions {
+			if offset == nil {
+				continue
+			}
+
+			agg.Partitions++
+			agg.Available += offset.NewestOffset - offset.OldestOffset
+			agg.Timestamp = maxInt64(agg.Timestamp, offset.Timestamp)
+		}
+
+		if agg.Partitions > 0 {
+			aggs = append(aggs, agg)
+		}
+	}
+
+	sort.Slice(aggs, func(i, j int) bool {
+		return aggs[i].Topic < aggs[j].Topic
+	})
+
+	return aggs
+}
+
+// metadataAggregate represents the broker metadata of a topic.
+type metadataAggregate struct {
+	Topic      string
+	Partitions int
+	Leaders    int
+	Replicas   int
+	Isr        int
+	Timestamp  int64
+}
+
+// IsrDiff returns the number of replicas out of sync.
+func (a *metadataAggregate) IsrDiff() int {
+	return a.Replicas - a.Isr
+}
+
+// aggregateBrokerMetadata totals the broker metadata per topic, sorted by topic.
+func aggregateBrokerMetadata(m *store.BrokerMetadata) []*metadataAggregate {
+	aggs := []*metadataAggregate{}
+	for topic, partitions := range *m {
+		agg := &metadataAggregate{Topic: topic}
+		for _, metadata := range partitions {
+			if metadata == nil {
+				continue
+			}
+
+			agg.Partitions++
+			if metadata.Leader >= 0 {
+				agg.Leaders++
+			}
+			agg.Replicas += len(metadata.Replicas)
+			agg.Isr += len(metadata.Isr)
+			agg.Timestamp = maxInt64(agg.Timestamp, metadata.Timestamp)
+		}
+
+		if agg.Partitions > 0 {
+			aggs = append(aggs, agg)
+		}
+	}
+
+	sort.Slice(aggs, func(i, j int) bool {
+		return aggs[i].Topic < aggs[j].Topic
+	})
+
+	return aggs
+}
+
+// consumerAggregate represents the consumer offsets of a group topic, or
+// of a group when the topic is empty.
+type consumerAggregate struct {
+	Group      string
+	Topic      string
+	Partitions int
+	Lag        int64
+	MaxLag     int64
+	Timestamp  int64
+}
+
+// aggregateConsumerOffsets totals the consumer offsets per group topic, or
+// per group with the group aggregation, sorted by group and topic.
+func aggregateConsumerOffsets(o *store.ConsumerOffsets, level AggregationLevel) []*consumerAggregate {
+	aggs := []*consumerAggregate{}
+	for group, topics := range *o {
+		groupAgg := &consumerAggregate{Group: group}
+		for topic, partitions := range topics {
+			agg := groupAgg
+			if level != AggregateGroup {
+				agg = &consumerAggregate{Group: group, Topic: topic}
+			}
+
+			for _, offset := range partitions {
+				if offset == nil {
+					continue
+				}
+
+				agg.Partitions++
+				agg.Lag += offset.Lag
+				agg.MaxLag = maxInt64(agg.MaxLag, offset.Lag)
+				agg.Timestamp = maxInt64(agg.Timestamp, offset.Timestamp)
+			}
+
+			if level != AggregateGroup && agg.Partitions > 0 {
+				aggs = append(aggs, agg)
+			}
+		}
+
+		if level == AggregateGroup && groupAgg.Partitions > 0 {
+			aggs = append(aggs, groupAgg)
+		}
+	}
+
+	sort.Slice(aggs, func(i, j int) bool {
+		if aggs[i].Group != aggs[j].Group {
+			return aggs[i].Group < aggs[j].Group
+		}
+		return aggs[i].Topic < aggs[j].Topic
+	})
+
+	return aggs
+}
+
+// collectionTime returns the collection timestamp in milliseconds, or now
+// when it is unknown.
+func collectionTime(ts, now int64) int64 {
+	if ts == 0 {
+		return now
+	}
+
+	return ts
+}
+
+func maxInt64(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,92 @@
+package reporter
+
+import (
+	"testing"
+
+	"github.com/msales/kage/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAggregation(t *testing.T) {
+	for _, s := range []string{"partition", "topic", "group"} {
+		level, err := ParseAggregation(s)
+
+		assert.NoError(t, err)
+		assert.Equal(t, AggregationLevel(s), level)
+	}
+
+	_, err := ParseAggregation("broker")
+	assert.Error(t, err)
+}
+
+func TestAggregateBrokerOffsets(t *testing.T) {
+	offsets := &store.BrokerOffsets{
+		"foo": []*store.BrokerOffset{{OldestOffset: 0, NewestOffset: 100, Timestamp: 1}, {OldestOffset: 50, NewestOffset: 100, Timestamp: 2}, nil},
+		"bar": []*store.BrokerOffset{{OldestOffset: 0, NewestOffset: 10}},
+		"nil": []*store.BrokerOffset{nil},
+	}
+
+	aggs := aggregateBrokerOffsets(offsets)
+
+	assert.Equal(t, []*brokerAggregate{
+		{Topic: "bar", Partitions: 1, Available: 10},
+		{Topic: "foo", Partitions: 2, Available: 150, Timestamp: 2},
+	}, aggs)
+}
+
+func TestAggregateBrokerMetadata(t *testing.T) {
+	metadata := &store.BrokerMetadata{
+		"foo": []*store.Metadata{
+			{Leader: 1, Replicas: []int32{1, 2}, Isr: []int32{1, 2}},
+			{Leader: -1, Replicas: []int32{1, 2}, Isr: []int32{1}},
+			nil,
+		},
+	}
+
+	aggs := aggregateBrokerMetadata(metadata)
+
+	assert.Equal(t, []*metadataAggregate{
+		{Topic: "foo", Partitions: 2, Leaders: 1, Replicas: 4, Isr: 3},
+	}, aggs)
+	assert.Equal(t, 1, aggs[0].IsrDiff())
+}
+
+func TestAggregateConsumerOffsets(t *testing.T) {
+	offsets := &store.ConsumerOffsets{
+		"foo": map[string][]*store.ConsumerOffset{
+			"a": {{Lag: 10}, {Lag: 30}, nil},
+			"b": {{Lag: 5}},
+		},
+		"bar": map[string][]*store.ConsumerOffset{
+			"a": {nil},
+		},
+	}
+
+	tests := []struct {
+		level AggregationLevel
+		want  []*consumerAggregate
+	}{
+		{
+			level: AggregateTopic,
+			want: []*consumerAggregate{
+				{Group: "foo", Topic: "a", Partitions: 2, Lag: 40, MaxLag: 30},
+				{Group: "foo", Topic: "b", Partitions: 1, Lag: 5, MaxLag: 5},
+			},
+		},
+		{
+			level: AggregateGroup,
+			want: []*consumerAggregate{
+				{Group: "foo", Partitions: 3, Lag: 45, MaxLag: 30},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, aggregateConsumerOffsets(offsets, tt.level))
+	}
+}
+
+func TestCollectionTime(t *testing.T) {
+	assert.Equal(t, int64(1000), collectionTime(0, 1000))
+	assert.Equal(t, int64(500), collectionTime(500, 1000))
+}
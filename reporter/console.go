@@ -3,32 +3,71 @@ package reporter
 import (
 	"fmt"
 	"io"
+	"sort"
 	"strings"
 
+	"github.com/msales/kage/metrics"
 	"github.com/msales/kage/store"
 )
 
+// ConsoleReporterFunc represents a configuration function for ConsoleReporter.
+type ConsoleReporterFunc func(c *ConsoleReporter)
+
+// ConsoleAggregation configures the aggregation level on a ConsoleReporter.
+func ConsoleAggregation(level AggregationLevel) ConsoleReporterFunc {
+	return func(c *ConsoleReporter) {
+		c.aggregation = level
+	}
+}
+
 // ConsoleReporter represents a console reporter.
 type ConsoleReporter struct {
+	aggregation AggregationLevel
+
 	w io.Writer
 }
 
 // NewConsoleReporter creates and returns a new ConsoleReporter.
-func NewConsoleReporter(w io.Writer) *ConsoleReporter {
-	return &ConsoleReporter{
-		w: w,
+func NewConsoleReporter(w io.Writer, opts ...ConsoleReporterFunc) *ConsoleReporter {
+	r := &ConsoleReporter{
+		aggregation: AggregatePartition,
+		w:           w,
+	}
+
+	for _, o := range opts {
+		o(r)
 	}
+
+	return r
 }
 
 // ReportBrokerOffsets reports a snapshot of the broker offsets.
-func (r ConsoleReporter) ReportBrokerOffsets(o *store.BrokerOffsets) {
+func (r ConsoleReporter) ReportBrokerOffsets(o *store.BrokerOffsets) error {
+	if r.aggregation != AggregatePartition {
+		for _, agg := range aggregateBrokerOffsets(o) {
+			if _, err := io.WriteString(
+				r.w,
+				fmt.Sprintf(
+					"%s partitions:%d available:%d \n",
+					agg.Topic,
+					agg.Partitions,
+					agg.Available,
+				),
+			); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
 	for topic, partitions := range *o {
 		for partition, offset := range partitions {
 			if offset == nil {
 				continue
 			}
 
-			io.WriteString(
+			if _, err := io.WriteString(
 				r.w,
 				fmt.Sprintf(
 					"%s:%d oldest:%d newest:%d available:%d \n",
@@ -38,20 +77,44 @@ func (r ConsoleReporter) ReportBrokerOffsets(o *store.BrokerOffsets) {
 					offset.NewestOffset,
 					offset.NewestOffset-offset.OldestOffset,
 				),
-			)
+			); err != nil {
+				return err
+			}
 		}
 	}
+
+	return nil
 }
 
 // ReportBrokerMetadata reports a snapshot of the broker metadata.
-func (r ConsoleReporter) ReportBrokerMetadata(m *store.BrokerMetadata) {
+func (r ConsoleReporter) ReportBrokerMetadata(m *store.BrokerMetadata) error {
+	if r.aggregation != AggregatePartition {
+		for _, agg := range aggregateBrokerMetadata(m) {
+			if _, err := io.WriteString(
+				r.w,
+				fmt.Sprintf(
+					"%s partitions:%d leaders:%d replicas:%d isr:%d \n",
+					agg.Topic,
+					agg.Partitions,
+					agg.Leaders,
+					agg.Replicas,
+					agg.Isr,
+				),
+			); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
 	for topic, partitions := range *m {
 		for partition, metadata := range partitions {
 			if metadata == nil {
 				continue
 			}
 
-			io.WriteString(
+			if _, err := io.WriteString(
 				r.w,
 				fmt.Sprintf(
 					"%s:%d leader:%d replicas:%s isr:%s \n",
@@ -61,13 +124,62 @@ func (r ConsoleReporter) ReportBrokerMetadata(m *store.BrokerMetadata) {
 					strings.Replace(strings.Trim(fmt.Sprint(metadata.Replicas), "[]"), " ", ",", -1),
 					strings.Replace(strings.Trim(fmt.Sprint(metadata.Isr), "[]"), " ", ",", -1),
 				),
-			)
+			); err != nil {
+				return err
+			}
 		}
 	}
+
+	return nil
+}
+
+// ReportBrokerStatus reports the status of the brokers.
+func (r ConsoleReporter) ReportBrokerStatus(s *store.BrokerStatuses) error {
+	for _, status := range *s {
+		if _, err := io.WriteString(
+			r.w,
+			fmt.Sprintf(
+				"broker:%d connected:%t controller:%t leaders:%d replicas:%d \n",
+				status.ID,
+				status.Connected,
+				status.Controller,
+				status.Leaders,
+				status.Replicas,
+			),
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 // ReportConsumerOffsets reports a snapshot of the consumer group offsets.
-func (r ConsoleReporter) ReportConsumerOffsets(o *store.ConsumerOffsets) {
+func (r ConsoleReporter) ReportConsumerOffsets(o *store.ConsumerOffsets) error {
+	if r.aggregation != AggregatePartition {
+		for _, agg := range aggregateConsumerOffsets(o, r.aggregation) {
+			name := agg.Group
+			if agg.Topic != "" {
+				name += " " + agg.Topic
+			}
+
+			if _, err := io.WriteString(
+				r.w,
+				fmt.Sprintf(
+					"%s partitions:%d lag:%d max_lag:%d \n",
+					name,
+					agg.Partitions,
+					agg.Lag,
+					agg.MaxLag,
+				),
+			); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
 	for group, topics := range *o {
 		for topic, partitions := range topics {
 			for partition, offset := range partitions {
@@ -75,7 +187,7 @@ func (r ConsoleReporter) ReportConsumerOffsets(o *store.ConsumerOffsets) {
 					continue
 				}
 
-				io.WriteString(
+				if _, err := io.WriteString(
 					r.w,
 					fmt.Sprintf(
 						"%s %s:%d offset:%d lag:%d \n",
@@ -85,8 +197,65 @@ func (r ConsoleReporter) ReportConsumerOffsets(o *store.ConsumerOffsets) {
 						offset.Offset,
 						offset.Lag,
 					),
-				)
+				); err != nil {
+					return err
+				}
 			}
 		}
 	}
+
+	return nil
+}
+
+// ReportOffsetRegressions reports newly detected consumer offset regressions.
+func (r ConsoleReporter) ReportOffsetRegressions(o *store.OffsetRegressions) error {
+	for _, regression := range *o {
+		if _, err := io.WriteString(
+			r.w,
+			fmt.Sprintf(
+				"%s %s:%d %s old:%d new:%d newest:%d \n",
+				regression.Group,
+				regression.Topic,
+				regression.Partition,
+				regression.Kind,
+				regression.OldOffset,
+				regression.NewOffset,
+				regression.NewestOffset,
+			),
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ReportMetrics reports a snapshot of the internal metrics.
+func (r ConsoleReporter) ReportMetrics(m *metrics.Snapshot) error {
+	for _, metric := range *m {
+		tags := []string{}
+		for key, value := range metric.Tags {
+			tags = append(tags, key+"="+value)
+		}
+		sort.Strings(tags)
+
+		values := []string{}
+		for key, value := range metric.Values {
+			values = append(values, fmt.Sprintf("%s:%v", key, value))
+		}
+		sort.Strings(values)
+
+		if _, err := io.WriteString(
+			r.w,
+			fmt.Sprintf(
+				"%s %s \n",
+				strings.Join(append([]string{metric.Name}, tags...), ","),
+				strings.Join(values, " "),
+			),
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
@@ -0,0 +1,15 @@
+package reporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsoleAggregation(t *testing.T) {
+	r := &ConsoleReporter{}
+
+	ConsoleAggregation(AggregateGroup)(r)
+
+	assert.Equal(t, AggregateGroup, r.aggregation)
+}
@@ -2,9 +2,11 @@ package reporter_test
 
 import (
 	"bytes"
+	"errors"
 	"testing"
 	"time"
 
+	"github.com/msales/kage/metrics"
 	"github.com/msales/kage/reporter"
 	"github.com/msales/kage/store"
 	"github.com/stretchr/testify/assert"
@@ -47,6 +49,16 @@ func TestConsoleReporter_ReportBrokerMetadata(t *testing.T) {
 	assert.Equal(t, "test:0 leader:1 replicas:1,2 isr:1,2 \n", buf.String())
 }
 
+func TestConsoleReporter_ReportBrokerStatus(t *testing.T) {
+	buf := bytes.NewBuffer([]byte{})
+	r := reporter.NewConsoleReporter(buf)
+
+	statuses := &store.BrokerStatuses{{ID: 1, Connected: true, Controller: true, Leaders: 3, Replicas: 6}}
+	r.ReportBrokerStatus(statuses)
+
+	assert.Equal(t, "broker:1 connected:true controller:true leaders:3 replicas:6 \n", buf.String())
+}
+
 func TestConsoleReporter_ReportConsumerOffsets(t *testing.T) {
 	buf := bytes.NewBuffer([]byte{})
 	r := reporter.NewConsoleReporter(buf)
@@ -66,3 +78,92 @@ func TestConsoleReporter_ReportConsumerOffsets(t *testing.T) {
 
 	assert.Equal(t, "foo test:0 offset:1000 lag:100 \n", buf.String())
 }
+
+func TestConsoleReporter_ReportTopicAggregates(t *testing.T) {
+	buf := bytes.NewBuffer([]byte{})
+	r := reporter.NewConsoleReporter(buf, reporter.ConsoleAggregation(reporter.AggregateTopic))
+
+	r.ReportBrokerOffsets(&store.BrokerOffsets{
+		"test": []*store.BrokerOffset{{OldestOffset: 0, NewestOffset: 1000}, {OldestOffset: 500, NewestOffset: 1000}},
+	})
+	r.ReportBrokerMetadata(&store.BrokerMetadata{
+		"test": []*store.Metadata{{Leader: 1, Replicas: []int32{1, 2}, Isr: []int32{1}}, {Leader: 2, Replicas: []int32{1, 2}, Isr: []int32{1, 2}}},
+	})
+	r.ReportConsumerOffsets(&store.ConsumerOffsets{
+		"foo": map[string][]*store.ConsumerOffset{
+			"test": {{Offset: 1000, Lag: 100}, {Offset: 900, Lag: 200}},
+		},
+	})
+
+	assert.Equal(t, "test partitions:2 available:1500 \n"+
+		"test partitions:2 leaders:2 replicas:4 isr:3 \n"+
+		"foo test partitions:2 lag:300 max_lag:200 \n", buf.String())
+}
+
+func TestConsoleReporter_ReportGroupAggregates(t *testing.T) {
+	buf := bytes.NewBuffer([]byte{})
+	r := reporter.NewConsoleReporter(buf, reporter.ConsoleAggregation(reporter.AggregateGroup))
+
+	r.ReportConsumerOffsets(&store.ConsumerOffsets{
+		"foo": map[string][]*store.ConsumerOffset{
+			"test":  {{Offset: 1000, Lag: 100}},
+			"other": {{Offset: 900, Lag: 200}},
+		},
+	})
+
+	assert.Equal(t, "foo partitions:2 lag:300 max_lag:200 \n", buf.String())
+}
+
+func TestConsoleReporter_ReportOffsetRegressions(t *testing.T) {
+	buf := bytes.NewBuffer([]byte{})
+	r := reporter.NewConsoleReporter(buf)
+
+	regressions := &store.OffsetRegressions{
+		{
+			Kind:         store.RegressionRewind,
+			Group:        "foo",
+			Topic:        "test",
+			Partition:    0,
+			OldOffset:    1000,
+			NewOffset:    100,
+			NewestOffset: 1200,
+			Timestamp:    time.Now().Unix() * 1000,
+		},
+	}
+	r.ReportOffsetRegressions(regressions)
+
+	assert.Equal(t, "foo test:0 rewind old:1000 new:100 newest:1200 \n", buf.String())
+}
+
+func TestConsoleReporter_ReportMetrics(t *testing.T) {
+	buf := bytes.NewBuffer([]byte{})
+	r := reporter.NewConsoleReporter(buf)
+
+	snapshot := &metrics.Snapshot{
+		{
+			Name:   "monitor.collect.errors",
+			Type:   metrics.TypeCounter,
+			Tags:   map[string]string{"phase": "broker_offsets"},
+			Values: map[string]float64{"count": 2},
+		},
+	}
+	r.ReportMetrics(snapshot)
+
+	assert.Equal(t, "monitor.collect.errors,phase=broker_offsets count:2 \n", buf.String())
+}
+
+func TestConsoleReporter_WriteError(t *testing.T) {
+	r := reporter.NewConsoleReporter(errorWriter{})
+
+	offsets := &store.BrokerOffsets{
+		"test": []*store.BrokerOffset{{OldestOffset: 0, NewestOffset: 1000}},
+	}
+
+	assert.Error(t, r.ReportBrokerOffsets(offsets))
+}
+
+type errorWriter struct{}
+
+func (errorWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("test error")
+}
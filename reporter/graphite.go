@@ -0,0 +1,294 @@
+package reporter
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/msales/kage/metrics"
+	"github.com/msales/kage/store"
+	"github.com/msales/kage/utils"
+	"gopkg.in/inconshreveable/log15.v2"
+)
+
+// DefaultGraphiteTemplate is the default path template of a GraphiteReporter.
+const DefaultGraphiteTemplate = "{prefix}.{type}.{group}.{topic}.{partition}.{metric}"
+
+// graphiteDialTimeout is the time allowed to connect to Graphite.
+const graphiteDialTimeout = 5 * time.Second
+
+// GraphiteReporterFunc represents a configuration function for GraphiteReporter.
+type GraphiteReporterFunc func(c *GraphiteReporter)
+
+// GraphitePrefix configures the path prefix on a GraphiteReporter.
+func GraphitePrefix(prefix string) GraphiteReporterFunc {
+	return func(c *GraphiteReporter) {
+		c.prefix = prefix
+	}
+}
+
+// GraphiteTemplate configures the path template on a GraphiteReporter.
+func GraphiteTemplate(template string) GraphiteReporterFunc {
+	return func(c *GraphiteReporter) {
+		c.template = template
+	}
+}
+
+// GraphiteLog configures the logger on a GraphiteReporter.
+func GraphiteLog(log log15.Logger) GraphiteReporterFunc {
+	return func(c *GraphiteReporter) {
+		c.log = log
+	}
+}
+
+// GraphiteReporter represents a Graphite plaintext reporter. The connection
+// is opened on the first report, and reopened when it drops.
+type GraphiteReporter struct {
+	prefix   string
+	template string
+
+	conn *graphiteConn
+
+	log log15.Logger
+}
+
+// NewGraphiteReporter creates and returns a new GraphiteReporter.
+func NewGraphiteReporter(addr string, opts ...GraphiteReporterFunc) *GraphiteReporter {
+	r := &GraphiteReporter{
+		template: DefaultGraphiteTemplate,
+		conn:     &graphiteConn{addr: addr},
+	}
+
+	for _, o := range opts {
+		o(r)
+	}
+
+	return r
+}
+
+// ReportBrokerOffsets reports a snapshot of the broker offsets.
+func (r GraphiteReporter) ReportBrokerOffsets(o *store.BrokerOffsets) error {
+	var buf bytes.Buffer
+	now := utils.Millis(time.Now())
+
+	for topic, partitions := range *o {
+		for partition, offset := range partitions {
+			if offset == nil {
+				continue
+			}
+
+			p := graphitePath{Type: "broker", Topic: topic, Partition: fmt.Sprint(partition)}
+			ts := collectionTime(offset.Timestamp, now) / 1000
+			r.writeLine(&buf, p, "oldest", offset.OldestOffset, ts)
+			r.writeLine(&buf, p, "newest", offset.NewestOffset, ts)
+			r.writeLine(&buf, p, "available", offset.NewestOffset-offset.OldestOffset, ts)
+		}
+	}
+
+	return r.send(buf.Bytes(), "offsets")
+}
+
+// ReportBrokerMetadata reports a snapshot of the broker metadata.
+func (r GraphiteReporter) ReportBrokerMetadata(m *store.BrokerMetadata) error {
+	var buf bytes.Buffer
+	now := utils.Millis(time.Now())
+
+	for topic, partitions := range *m {
+		for partition, metadata := range partitions {
+			if metadata == nil {
+				continue
+			}
+
+			leaders := 1
+			if metadata.Leader < 0 {
+				leaders = 0
+			}
+
+			p := graphitePath{Type: "metadata", Topic: topic, Partition: fmt.Sprint(partition)}
+			ts := collectionTime(metadata.Timestamp, now) / 1000
+			r.writeLine(&buf, p, "leaders", leaders, ts)
+			r.writeLine(&buf, p, "replicas", len(metadata.Replicas), ts)
+			r.writeLine(&buf, p, "isr", len(metadata.Isr), ts)
+			r.writeLine(&buf, p, "isr_diff", math.Abs(float64(len(metadata.Isr)-len(metadata.Replicas))), ts)
+		}
+	}
+
+	return r.send(buf.Bytes(), "metadata")
+}
+
+// ReportBrokerStatus reports the status of the brokers.
+func (r GraphiteReporter) ReportBrokerStatus(s *store.BrokerStatuses) error {
+	var buf bytes.Buffer
+	now := utils.Millis(time.Now())
+
+	for _, status := range *s {
+		p := graphitePath{Type: "brokers"}
+		id := fmt.Sprint(status.ID)
+		ts := collectionTime(status.Timestamp, now) / 1000
+		r.writeLine(&buf, p, id+".connected", boolToInt(status.Connected), ts)
+		r.writeLine(&buf, p, id+".controller", boolToInt(status.Controller), ts)
+		r.writeLine(&buf, p, id+".leaders", status.Leaders, ts)
+		r.writeLine(&buf, p, id+".replicas", status.Replicas, ts)
+	}
+
+	return r.send(buf.Bytes(), "broker-status")
+}
+
+// ReportConsumerOffsets reports a snapshot of the consumer group offsets.
+func (r GraphiteReporter) ReportConsumerOffsets(o *store.ConsumerOffsets) error {
+	var buf bytes.Buffer
+	now := utils.Millis(time.Now())
+
+	for group, topics := range *o {
+		for topic, partitions := range topics {
+			for partition, offset := range partitions {
+				if offset == nil {
+					continue
+				}
+
+				p := graphitePath{Type: "consumer", Group: group, Topic: topic, Partition: fmt.Sprint(partition)}
+				ts := collectionTime(offset.Timestamp, now) / 1000
+				r.writeLine(&buf, p, "offset", offset.Offset, ts)
+				r.writeLine(&buf, p, "lag", offset.Lag, ts)
+			}
+		}
+	}
+
+	return r.send(buf.Bytes(), "consumer-offsets")
+}
+
+// ReportOffsetRegressions reports newly detected consumer offset regressions.
+func (r GraphiteReporter) ReportOffsetRegressions(o *store.OffsetRegressions) error {
+	var buf bytes.Buffer
+
+	for _, regression := range *o {
+		p := graphitePath{
+			Type:      "regression",
+			Group:     regression.Group,
+			Topic:     regression.Topic,
+			Partition: fmt.Sprint(regression.Partition),
+		}
+		r.writeLine(&buf, p, regression.Kind, 1, regression.Timestamp/1000)
+	}
+
+	return r.send(buf.Bytes(), "offset-regressions")
+}
+
+// ReportMetrics reports a snapshot of the internal metrics.
+func (r GraphiteReporter) ReportMetrics(m *metrics.Snapshot) error {
+	var buf bytes.Buffer
+	ts := time.Now().Unix()
+
+	for _, metric := range *m {
+		keys := make([]string, 0, len(metric.Tags))
+		for key := range metric.Tags {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		parts := []string{escapeGraphite(metric.Name)}
+		for _, key := range keys {
+			parts = append(parts, escapeGraphite(metric.Tags[key]))
+		}
+
+		for name, value := range metric.Values {
+			p := graphitePath{Type: "kage"}
+			r.writeLine(&buf, p, strings.Join(append(parts, name), "."), value, ts)
+		}
+	}
+
+	return r.send(buf.Bytes(), "metrics")
+}
+
+// writeLine writes a plaintext protocol line to the buffer.
+func (r GraphiteReporter) writeLine(buf *bytes.Buffer, p graphitePath, metric string, value interface{}, ts int64) {
+	fmt.Fprintf(buf, "%s %v %d\n", p.Render(r.template, r.prefix, metric), value, ts)
+}
+
+func (r GraphiteReporter) send(data []byte, report string) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	if err := r.conn.Write(data); err != nil {
+		r.log.Error("graphite: " + report + ":" + err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// graphitePath represents the values of a path template.
+type graphitePath struct {
+	Type      string
+	Group     string
+	Topic     string
+	Partition string
+}
+
+// Render renders the path template. Segments left empty are removed.
+func (p graphitePath) Render(template, prefix, metric string) string {
+	path := strings.NewReplacer(
+		"{prefix}", prefix,
+		"{type}", p.Type,
+		"{group}", escapeGraphite(p.Group),
+		"{topic}", escapeGraphite(p.Topic),
+		"{partition}", p.Partition,
+		"{metric}", metric,
+	).Replace(template)
+
+	segments := []string{}
+	for _, s := range strings.Split(path, ".") {
+		if s != "" {
+			segments = append(segments, s)
+		}
+	}
+
+	return strings.Join(segments, ".")
+}
+
+// graphiteReplacer replaces the characters with a meaning in the Graphite path.
+var graphiteReplacer = strings.NewReplacer(".", "_", " ", "_", "\t", "_", "\n", "_")
+
+// escapeGraphite escapes a Graphite path segment.
+func escapeGraphite(s string) string {
+	return graphiteReplacer.Replace(s)
+}
+
+// graphiteConn represents a reconnecting Graphite connection.
+type graphiteConn struct {
+	addr string
+
+	lock sync.Mutex
+	conn net.Conn
+}
+
+// Write writes the data to Graphite, reconnecting once if the write fails.
+func (c *graphiteConn) Write(data []byte) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		if c.conn == nil {
+			if c.conn, err = net.DialTimeout("tcp", c.addr, graphiteDialTimeout); err != nil {
+				c.conn = nil
+				continue
+			}
+		}
+
+		if _, err = c.conn.Write(data); err == nil {
+			return nil
+		}
+
+		c.conn.Close()
+		c.conn = nil
+	}
+
+	return err
+}
@@ -0,0 +1,61 @@
+package reporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/inconshreveable/log15.v2"
+)
+
+func TestGraphitePrefix(t *testing.T) {
+	r := &GraphiteReporter{}
+
+	GraphitePrefix("kafka")(r)
+
+	assert.Equal(t, "kafka", r.prefix)
+}
+
+func TestGraphiteTemplate(t *testing.T) {
+	r := &GraphiteReporter{}
+
+	GraphiteTemplate("{prefix}.{topic}.{partition}.{metric}")(r)
+
+	assert.Equal(t, "{prefix}.{topic}.{partition}.{metric}", r.template)
+}
+
+func TestGraphiteLog(t *testing.T) {
+	log := log15.New()
+	r := &GraphiteReporter{}
+
+	GraphiteLog(log)(r)
+
+	assert.Equal(t, log, r.log)
+}
+
+func TestGraphitePath_Render(t *testing.T) {
+	tests := []struct {
+		template string
+		path     graphitePath
+		want     string
+	}{
+		{
+			template: DefaultGraphiteTemplate,
+			path:     graphitePath{Type: "broker", Topic: "test.topic", Partition: "0"},
+			want:     "kafka.broker.test_topic.0.newest",
+		},
+		{
+			template: DefaultGraphiteTemplate,
+			path:     graphitePath{Type: "consumer", Group: "my group", Topic: "test", Partition: "1"},
+			want:     "kafka.consumer.my_group.test.1.newest",
+		},
+		{
+			template: "{prefix}.{topic}.{partition}.{metric}",
+			path:     graphitePath{Type: "broker", Topic: "test", Partition: "2"},
+			want:     "kafka.test.2.newest",
+		},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, tt.path.Render(tt.template, "kafka", "newest"))
+	}
+}
@@ -0,0 +1,251 @@
+package reporter_test
+
+import (
+	"bufio"
+	"net"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/msales/kage/metrics"
+	"github.com/msales/kage/reporter"
+	"github.com/msales/kage/store"
+	"github.com/msales/kage/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// graphiteServer accepts connections, sending the received lines to a channel.
+type graphiteServer struct {
+	ln    net.Listener
+	lines chan string
+	conns chan net.Conn
+}
+
+func newGraphiteServer(t *testing.T) *graphiteServer {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &graphiteServer{ln: ln, lines: make(chan string, 100), conns: make(chan net.Conn, 10)}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			s.conns <- conn
+
+			go func() {
+				scanner := bufio.NewScanner(conn)
+				for scanner.Scan() {
+					s.lines <- scanner.Text()
+				}
+			}()
+		}
+	}()
+
+	return s
+}
+
+func (s *graphiteServer) Read(t *testing.T, n int) []string {
+	lines := []string{}
+	for i := 0; i < n; i++ {
+		select {
+		case line := <-s.lines:
+			lines = append(lines, line)
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for graphite lines")
+		}
+	}
+	sort.Strings(lines)
+
+	return lines
+}
+
+func (s *graphiteServer) Close() {
+	s.ln.Close()
+}
+
+func stripTimestamps(lines []string) []string {
+	stripped := make([]string, len(lines))
+	for i, line := range lines {
+		stripped[i] = line[:strings.LastIndex(line, " ")]
+	}
+
+	return stripped
+}
+
+func TestGraphiteReporter_ReportBrokerOffsets(t *testing.T) {
+	srv := newGraphiteServer(t)
+	defer srv.Close()
+
+	r := reporter.NewGraphiteReporter(srv.ln.Addr().String(), reporter.GraphitePrefix("kafka"), reporter.GraphiteLog(testutil.Logger))
+
+	offsets := &store.BrokerOffsets{
+		"test.topic": []*store.BrokerOffset{{OldestOffset: 10, NewestOffset: 1000}},
+		"nil":        []*store.BrokerOffset{nil},
+	}
+	assert.NoError(t, r.ReportBrokerOffsets(offsets))
+
+	assert.Equal(t, []string{
+		"kafka.broker.test_topic.0.available 990",
+		"kafka.broker.test_topic.0.newest 1000",
+		"kafka.broker.test_topic.0.oldest 10",
+	}, stripTimestamps(srv.Read(t, 3)))
+}
+
+func TestGraphiteReporter_ReportBrokerMetadata(t *testing.T) {
+	srv := newGraphiteServer(t)
+	defer srv.Close()
+
+	r := reporter.NewGraphiteReporter(srv.ln.Addr().String(), reporter.GraphitePrefix("kafka"), reporter.GraphiteLog(testutil.Logger))
+
+	metadata := &store.BrokerMetadata{
+		"test": []*store.Metadata{{Leader: -1, Replicas: []int32{1, 2}, Isr: []int32{1}}},
+	}
+	assert.NoError(t, r.ReportBrokerMetadata(metadata))
+
+	assert.Equal(t, []string{
+		"kafka.metadata.test.0.isr 1",
+		"kafka.metadata.test.0.isr_diff 1",
+		"kafka.metadata.test.0.leaders 0",
+		"kafka.metadata.test.0.replicas 2",
+	}, stripTimestamps(srv.Read(t, 4)))
+}
+
+func TestGraphiteReporter_ReportBrokerStatus(t *testing.T) {
+	srv := newGraphiteServer(t)
+	defer srv.Close()
+
+	r := reporter.NewGraphiteReporter(srv.ln.Addr().String(), reporter.GraphitePrefix("kafka"), reporter.GraphiteLog(testutil.Logger))
+
+	statuses := &store.BrokerStatuses{{ID: 1, Connected: false, Controller: true, Leaders: 3, Replicas: 6}}
+	assert.NoError(t, r.ReportBrokerStatus(statuses))
+
+	assert.Equal(t, []string{
+		"kafka.brokers.1.connected 0",
+		"kafka.brokers.1.controller 1",
+		"kafka.brokers.1.leaders 3",
+		"kafka.brokers.1.replicas 6",
+	}, stripTimestamps(srv.Read(t, 4)))
+}
+
+func TestGraphiteReporter_ReportConsumerOffsets(t *testing.T) {
+	srv := newGraphiteServer(t)
+	defer srv.Close()
+
+	r := reporter.NewGraphiteReporter(srv.ln.Addr().String(),
+		reporter.GraphitePrefix("kafka"),
+		reporter.GraphiteTemplate("{prefix}.{group}.{topic}.{partition}.{metric}"),
+		reporter.GraphiteLog(testutil.Logger),
+	)
+
+	offsets := &store.ConsumerOffsets{
+		"foo.bar": map[string][]*store.ConsumerOffset{
+			"test": {{Offset: 1000, Lag: 100}, nil},
+		},
+	}
+	assert.NoError(t, r.ReportConsumerOffsets(offsets))
+
+	assert.Equal(t, []string{
+		"kafka.foo_bar.test.0.lag 100",
+		"kafka.foo_bar.test.0.offset 1000",
+	}, stripTimestamps(srv.Read(t, 2)))
+}
+
+func TestGraphiteReporter_ReportCollectionTime(t *testing.T) {
+	srv := newGraphiteServer(t)
+	defer srv.Close()
+
+	r := reporter.NewGraphiteReporter(srv.ln.Addr().String(), reporter.GraphitePrefix("kafka"), reporter.GraphiteLog(testutil.Logger))
+
+	offsets := &store.ConsumerOffsets{
+		"foo": map[string][]*store.ConsumerOffset{
+			"test": {{Offset: 1000, Lag: 100, Timestamp: 1500000000000}},
+		},
+	}
+	assert.NoError(t, r.ReportConsumerOffsets(offsets))
+
+	assert.Equal(t, []string{
+		"kafka.consumer.foo.test.0.lag 100 1500000000",
+		"kafka.consumer.foo.test.0.offset 1000 1500000000",
+	}, srv.Read(t, 2))
+}
+
+func TestGraphiteReporter_ReportOffsetRegressions(t *testing.T) {
+	srv := newGraphiteServer(t)
+	defer srv.Close()
+
+	r := reporter.NewGraphiteReporter(srv.ln.Addr().String(), reporter.GraphitePrefix("kafka"), reporter.GraphiteLog(testutil.Logger))
+
+	regressions := &store.OffsetRegressions{
+		{Kind: store.RegressionJump, Group: "foo", Topic: "test", Partition: 1, Timestamp: 1500000000000},
+	}
+	assert.NoError(t, r.ReportOffsetRegressions(regressions))
+
+	assert.Equal(t, []string{"kafka.regression.foo.test.1.jump 1 1500000000"}, srv.Read(t, 1))
+}
+
+func TestGraphiteReporter_ReportMetrics(t *testing.T) {
+	srv := newGraphiteServer(t)
+	defer srv.Close()
+
+	r := reporter.NewGraphiteReporter(srv.ln.Addr().String(), reporter.GraphitePrefix("kafka"), reporter.GraphiteLog(testutil.Logger))
+
+	snapshot := &metrics.Snapshot{
+		{
+			Name:   "monitor.collect.errors",
+			Type:   metrics.TypeCounter,
+			Tags:   map[string]string{"phase": "broker_offsets"},
+			Values: map[string]float64{"count": 2},
+		},
+	}
+	assert.NoError(t, r.ReportMetrics(snapshot))
+
+	assert.Equal(t, []string{"kafka.kage.monitor_collect_errors.broker_offsets.count 2"}, stripTimestamps(srv.Read(t, 1)))
+}
+
+func TestGraphiteReporter_Reconnects(t *testing.T) {
+	srv := newGraphiteServer(t)
+	defer srv.Close()
+
+	r := reporter.NewGraphiteReporter(srv.ln.Addr().String(), reporter.GraphitePrefix("kafka"), reporter.GraphiteLog(testutil.Logger))
+
+	offsets := &store.ConsumerOffsets{
+		"foo": map[string][]*store.ConsumerOffset{"test": {{Offset: 1000, Lag: 100}}},
+	}
+	assert.NoError(t, r.ReportConsumerOffsets(offsets))
+	srv.Read(t, 2)
+
+	conn := <-srv.conns
+	conn.Close()
+
+	// Writes to a dropped connection may succeed until the reset is received
+	for i := 0; i < 10; i++ {
+		r.ReportConsumerOffsets(offsets)
+
+		select {
+		case <-srv.conns:
+			return
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+
+	t.Fatal("reporter did not reconnect")
+}
+
+func TestGraphiteReporter_ConnectError(t *testing.T) {
+	srv := newGraphiteServer(t)
+	addr := srv.ln.Addr().String()
+	srv.Close()
+
+	r := reporter.NewGraphiteReporter(addr, reporter.GraphiteLog(testutil.Logger))
+
+	offsets := &store.BrokerOffsets{
+		"test": []*store.BrokerOffset{{OldestOffset: 0, NewestOffset: 1000}},
+	}
+
+	assert.Error(t, r.ReportBrokerOffsets(offsets))
+}
@@ -3,16 +3,82 @@ package reporter
 import (
 	"fmt"
 	"math"
+	"sort"
 	"time"
 
 	"github.com/influxdata/influxdb/client/v2"
+	"github.com/msales/kage/metrics"
 	"github.com/msales/kage/store"
+	"github.com/msales/kage/utils"
 	"gopkg.in/inconshreveable/log15.v2"
 )
 
+// Influx report types, used to configure the measurement and retention
+// policy of each report.
+const (
+	InfluxBrokerOffset     = "broker_offset"
+	InfluxBrokerMetadata   = "broker_metadata"
+	InfluxBrokerStatus     = "broker_status"
+	InfluxConsumerOffset   = "consumer_offset"
+	InfluxOffsetRegression = "offset_regression"
+	InfluxMetric           = "metric"
+)
+
+// influxTypes maps the report types to their type tag.
+var influxTypes = map[string]string{
+	InfluxBrokerOffset:     "BrokerOffset",
+	InfluxBrokerMetadata:   "BrokerMetadata",
+	InfluxBrokerStatus:     "BrokerStatus",
+	InfluxConsumerOffset:   "ConsumerOffset",
+	InfluxOffsetRegression: "OffsetRegression",
+	InfluxMetric:           "Metric",
+}
+
+// CheckInfluxReports checks the keys of the map are Influx report types.
+func CheckInfluxReports(reports map[string]string) error {
+	names := make([]string, 0, len(reports))
+	for name := range reports {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if _, ok := influxTypes[name]; !ok {
+			return fmt.Errorf("influx: unknown report type \"%s\"", name)
+		}
+	}
+
+	return nil
+}
+
 // InfluxReporterFunc represents a configuration function for InfluxReporter.
 type InfluxReporterFunc func(c *InfluxReporter)
 
+// Aggregation configures the aggregation level on an InfluxReporter.
+func Aggregation(level AggregationLevel) InfluxReporterFunc {
+	return func(c *InfluxReporter) {
+		c.aggregation = level
+	}
+}
+
+// AsFields configures the values written as fields instead of tags on an InfluxReporter.
+func AsFields(names ...string) InfluxReporterFunc {
+	return func(c *InfluxReporter) {
+		for _, name := range names {
+			c.asFields[name] = true
+		}
+	}
+}
+
+// AsTags configures the values written as tags instead of fields on an InfluxReporter.
+func AsTags(names ...string) InfluxReporterFunc {
+	return func(c *InfluxReporter) {
+		for _, name := range names {
+			c.asTags[name] = true
+		}
+	}
+}
+
 // Database configures the database on an InfluxReporter.
 func Database(db string) InfluxReporterFunc {
 	return func(c *InfluxReporter) {
@@ -27,6 +93,14 @@ func Log(log log15.Logger) InfluxReporterFunc {
 	}
 }
 
+// Measurements configures the measurement name of each report type on an
+// InfluxReporter. Report types without a measurement use the metric name.
+func Measurements(measurements map[string]string) InfluxReporterFunc {
+	return func(c *InfluxReporter) {
+		c.measurements = measurements
+	}
+}
+
 // Metric configures the metric name on an InfluxReporter.
 func Metric(metric string) InfluxReporterFunc {
 	return func(c *InfluxReporter) {
@@ -34,6 +108,14 @@ func Metric(metric string) InfluxReporterFunc {
 	}
 }
 
+// Policies configures the retention policy name of each report type on an
+// InfluxReporter. Report types without a policy use the retention policy.
+func Policies(policies map[string]string) InfluxReporterFunc {
+	return func(c *InfluxReporter) {
+		c.policies = policies
+	}
+}
+
 // Policy configures the retention policy name on an InfluxReporter.
 func Policy(policy string) InfluxReporterFunc {
 	return func(c *InfluxReporter) {
@@ -50,11 +132,16 @@ func Tags(tags map[string]string) InfluxReporterFunc {
 
 // InfluxReporter represents an InfluxDB reporter.
 type InfluxReporter struct {
-	database string
+	database    string
+	aggregation AggregationLevel
 
-	metric string
-	policy string
-	tags   map[string]string
+	metric       string
+	measurements map[string]string
+	policy       string
+	policies     map[string]string
+	tags         map[string]string
+	asTags       map[string]bool
+	asFields     map[string]bool
 
 	client client.Client
 
@@ -64,7 +151,10 @@ type InfluxReporter struct {
 // NewInfluxReporter creates and returns a new NewInfluxReporter.
 func NewInfluxReporter(client client.Client, opts ...InfluxReporterFunc) *InfluxReporter {
 	r := &InfluxReporter{
-		client: client,
+		aggregation: AggregatePartition,
+		asTags:      map[string]bool{},
+		asFields:    map[string]bool{},
+		client:      client,
 	}
 
 	for _, o := range opts {
@@ -75,106 +165,187 @@ func NewInfluxReporter(client client.Client, opts ...InfluxReporterFunc) *Influx
 }
 
 // ReportBrokerOffsets reports a snapshot of the broker offsets.
-func (r InfluxReporter) ReportBrokerOffsets(o *store.BrokerOffsets) {
-	pts, _ := client.NewBatchPoints(client.BatchPointsConfig{
-		Database:        r.database,
-		Precision:       "s",
-		RetentionPolicy: r.policy,
-	})
+func (r InfluxReporter) ReportBrokerOffsets(o *store.BrokerOffsets) error {
+	pts := r.newBatchPoints(InfluxBrokerOffset)
+
+	if r.aggregation == AggregatePartition {
+		r.addBrokerOffsets(pts, o)
+	} else {
+		r.addBrokerOffsetTotals(pts, o)
+	}
 
+	if err := r.client.Write(pts); err != nil {
+		r.log.Error("influx: offsets:" + err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (r InfluxReporter) addBrokerOffsets(pts client.BatchPoints, o *store.BrokerOffsets) {
+	now := utils.Millis(time.Now())
 	for topic, partitions := range *o {
 		for partition, offset := range partitions {
 			if offset == nil {
 				continue
 			}
 
-			tags := map[string]string{
-				"type":      "BrokerOffset",
-				"topic":     topic,
-				"partition": fmt.Sprint(partition),
-			}
-
-			for key, value := range r.tags {
-				tags[key] = value
-			}
-
-			pt, _ := client.NewPoint(
-				r.metric,
-				tags,
+			r.addPoint(
+				pts,
+				InfluxBrokerOffset,
+				map[string]interface{}{
+					"topic":     topic,
+					"partition": partition,
+				},
 				map[string]interface{}{
 					"oldest":    offset.OldestOffset,
 					"newest":    offset.NewestOffset,
 					"available": offset.NewestOffset - offset.OldestOffset,
 				},
-				time.Now(),
+				time.Unix(0, collectionTime(offset.Timestamp, now)*int64(time.Millisecond)),
 			)
-
-			pts.AddPoint(pt)
 		}
 	}
+}
 
-	if err := r.client.Write(pts); err != nil {
-		r.log.Error("influx: offsets:" + err.Error())
+func (r InfluxReporter) addBrokerOffsetTotals(pts client.BatchPoints, o *store.BrokerOffsets) {
+	now := utils.Millis(time.Now())
+	for _, agg := range aggregateBrokerOffsets(o) {
+		r.addPoint(
+			pts,
+			InfluxBrokerOffset,
+			map[string]interface{}{
+				"topic": agg.Topic,
+			},
+			map[string]interface{}{
+				"partitions": agg.Partitions,
+				"available":  agg.Available,
+			},
+			time.Unix(0, collectionTime(agg.Timestamp, now)*int64(time.Millisecond)),
+		)
 	}
 }
 
 // ReportBrokerMetadata reports a snapshot of the broker metadata.
-func (r InfluxReporter) ReportBrokerMetadata(m *store.BrokerMetadata) {
-	pts, _ := client.NewBatchPoints(client.BatchPointsConfig{
-		Database:        r.database,
-		Precision:       "s",
-		RetentionPolicy: r.policy,
-	})
+func (r InfluxReporter) ReportBrokerMetadata(m *store.BrokerMetadata) error {
+	pts := r.newBatchPoints(InfluxBrokerMetadata)
+
+	if r.aggregation == AggregatePartition {
+		r.addBrokerMetadata(pts, m)
+	} else {
+		r.addBrokerMetadataTotals(pts, m)
+	}
+
+	if err := r.client.Write(pts); err != nil {
+		r.log.Error("influx: metadata:" + err.Error())
+		return err
+	}
 
+	return nil
+}
+
+func (r InfluxReporter) addBrokerMetadata(pts client.BatchPoints, m *store.BrokerMetadata) {
+	now := utils.Millis(time.Now())
 	for topic, partitions := range *m {
 		for partition, metadata := range partitions {
 			if metadata == nil {
 				continue
 			}
 
-			tags := map[string]string{
-				"type":      "BrokerMetadata",
-				"topic":     topic,
-				"partition": fmt.Sprint(partition),
-			}
-
-			for key, value := range r.tags {
-				tags[key] = value
-			}
-
 			leaders := 1
 			if metadata.Leader < 0 {
 				leaders = 0
 			}
-			pt, _ := client.NewPoint(
-				r.metric,
-				tags,
+			r.addPoint(
+				pts,
+				InfluxBrokerMetadata,
+				map[string]interface{}{
+					"topic":     topic,
+					"partition": partition,
+				},
 				map[string]interface{}{
 					"leaders":  leaders,
 					"replicas": len(metadata.Replicas),
 					"isr":      len(metadata.Isr),
 					"isr_diff": math.Abs(float64(len(metadata.Isr) - len(metadata.Replicas))),
 				},
-				time.Now(),
+				time.Unix(0, collectionTime(metadata.Timestamp, now)*int64(time.Millisecond)),
 			)
-
-			pts.AddPoint(pt)
 		}
 	}
+}
+
+func (r InfluxReporter) addBrokerMetadataTotals(pts client.BatchPoints, m *store.BrokerMetadata) {
+	now := utils.Millis(time.Now())
+	for _, agg := range aggregateBrokerMetadata(m) {
+		r.addPoint(
+			pts,
+			InfluxBrokerMetadata,
+			map[string]interface{}{
+				"topic": agg.Topic,
+			},
+			map[string]interface{}{
+				"partitions": agg.Partitions,
+				"leaders":    agg.Leaders,
+				"replicas":   agg.Replicas,
+				"isr":        agg.Isr,
+				"isr_diff":   float64(agg.IsrDiff()),
+			},
+			time.Unix(0, collectionTime(agg.Timestamp, now)*int64(time.Millisecond)),
+		)
+	}
+}
+
+// ReportBrokerStatus reports the status of the brokers.
+func (r InfluxReporter) ReportBrokerStatus(s *store.BrokerStatuses) error {
+	pts := r.newBatchPoints(InfluxBrokerStatus)
+
+	now := utils.Millis(time.Now())
+	for _, status := range *s {
+		r.addPoint(
+			pts,
+			InfluxBrokerStatus,
+			map[string]interface{}{
+				"broker": status.ID,
+			},
+			map[string]interface{}{
+				"connected":  boolToInt(status.Connected),
+				"controller": boolToInt(status.Controller),
+				"leaders":    status.Leaders,
+				"replicas":   status.Replicas,
+			},
+			time.Unix(0, collectionTime(status.Timestamp, now)*int64(time.Millisecond)),
+		)
+	}
 
 	if err := r.client.Write(pts); err != nil {
-		r.log.Error("influx: metadata:" + err.Error())
+		r.log.Error("influx: broker-status:" + err.Error())
+		return err
 	}
+
+	return nil
 }
 
 // ReportConsumerOffsets reports a snapshot of the consumer group offsets.
-func (r InfluxReporter) ReportConsumerOffsets(o *store.ConsumerOffsets) {
-	pts, _ := client.NewBatchPoints(client.BatchPointsConfig{
-		Database:        r.database,
-		Precision:       "s",
-		RetentionPolicy: r.policy,
-	})
+func (r InfluxReporter) ReportConsumerOffsets(o *store.ConsumerOffsets) error {
+	pts := r.newBatchPoints(InfluxConsumerOffset)
 
+	if r.aggregation == AggregatePartition {
+		r.addConsumerOffsets(pts, o)
+	} else {
+		r.addConsumerOffsetTotals(pts, o)
+	}
+
+	if err := r.client.Write(pts); err != nil {
+		r.log.Error("influx: consumer-offsets:" + err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (r InfluxReporter) addConsumerOffsets(pts client.BatchPoints, o *store.ConsumerOffsets) {
+	now := utils.Millis(time.Now())
 	for group, topics := range *o {
 		for topic, partitions := range topics {
 			for partition, offset := range partitions {
@@ -182,33 +353,174 @@ func (r InfluxReporter) ReportConsumerOffsets(o *store.ConsumerOffsets) {
 					continue
 				}
 
-				tags := map[string]string{
-					"type":      "ConsumerOffset",
-					"group":     group,
-					"topic":     topic,
-					"partition": fmt.Sprint(partition),
-				}
-
-				for key, value := range r.tags {
-					tags[key] = value
-				}
-
-				pt, _ := client.NewPoint(
-					r.metric,
-					tags,
+				r.addPoint(
+					pts,
+					InfluxConsumerOffset,
+					map[string]interface{}{
+						"group":     group,
+						"topic":     topic,
+						"partition": partition,
+					},
 					map[string]interface{}{
 						"offset": offset.Offset,
 						"lag":    offset.Lag,
 					},
-					time.Now(),
+					time.Unix(0, collectionTime(offset.Timestamp, now)*int64(time.Millisecond)),
 				)
-
-				pts.AddPoint(pt)
 			}
 		}
 	}
+}
+
+func (r InfluxReporter) addConsumerOffsetTotals(pts client.BatchPoints, o *store.ConsumerOffsets) {
+	now := utils.Millis(time.Now())
+	for _, agg := range aggregateConsumerOffsets(o, r.aggregation) {
+		tags := map[string]interface{}{
+			"group": agg.Group,
+		}
+		if agg.Topic != "" {
+			tags["topic"] = agg.Topic
+		}
+
+		r.addPoint(
+			pts,
+			InfluxConsumerOffset,
+			tags,
+			map[string]interface{}{
+				"partitions": agg.Partitions,
+				"lag":        agg.Lag,
+				"max_lag":    agg.MaxLag,
+			},
+			time.Unix(0, collectionTime(agg.Timestamp, now)*int64(time.Millisecond)),
+		)
+	}
+}
+
+// ReportOffsetRegressions reports newly detected consumer offset regressions.
+func (r InfluxReporter) ReportOffsetRegressions(o *store.OffsetRegressions) error {
+	pts := r.newBatchPoints(InfluxOffsetRegression)
+
+	now := utils.Millis(time.Now())
+	for _, regression := range *o {
+		r.addPoint(
+			pts,
+			InfluxOffsetRegression,
+			map[string]interface{}{
+				"kind":      regression.Kind,
+				"group":     regression.Group,
+				"topic":     regression.Topic,
+				"partition": regression.Partition,
+			},
+			map[string]interface{}{
+				"old":    regression.OldOffset,
+				"new":    regression.NewOffset,
+				"newest": regression.NewestOffset,
+			},
+			time.Unix(0, collectionTime(regression.Timestamp, now)*int64(time.Millisecond)),
+		)
+	}
 
 	if err := r.client.Write(pts); err != nil {
-		r.log.Error("influx: consumer-offsets:" + err.Error())
+		r.log.Error("influx: offset-regressions:" + err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// ReportMetrics reports a snapshot of the internal metrics.
+func (r InfluxReporter) ReportMetrics(m *metrics.Snapshot) error {
+	pts := r.newBatchPoints(InfluxMetric)
+
+	now := time.Now()
+	for _, metric := range *m {
+		tags := map[string]interface{}{
+			"name": metric.Name,
+		}
+
+		for key, value := range metric.Tags {
+			tags[key] = value
+		}
+
+		fields := make(map[string]interface{}, len(metric.Values))
+		for key, value := range metric.Values {
+			fields[key] = value
+		}
+
+		r.addPoint(pts, InfluxMetric, tags, fields, now)
+	}
+
+	if err := r.client.Write(pts); err != nil {
+		r.log.Error("influx: metrics:" + err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// newBatchPoints creates the batch points of the report type, with its
+// retention policy.
+func (r InfluxReporter) newBatchPoints(report string) client.BatchPoints {
+	policy, ok := r.policies[report]
+	if !ok {
+		policy = r.policy
 	}
+
+	pts, _ := client.NewBatchPoints(client.BatchPointsConfig{
+		Database:        r.database,
+		Precision:       "s",
+		RetentionPolicy: policy,
+	})
+
+	return pts
+}
+
+// addPoint adds a point of the report type to the batch points, moving
+// the configured values between tags and fields. Points left without
+// fields are skipped.
+func (r InfluxReporter) addPoint(pts client.BatchPoints, report string, tags, fields map[string]interface{}, ts time.Time) {
+	ptTags := map[string]string{"type": influxTypes[report]}
+	ptFields := make(map[string]interface{}, len(fields))
+
+	for key, value := range tags {
+		if r.asFields[key] {
+			ptFields[key] = value
+			continue
+		}
+		ptTags[key] = fmt.Sprint(value)
+	}
+
+	for key, value := range fields {
+		if r.asTags[key] {
+			ptTags[key] = fmt.Sprint(value)
+			continue
+		}
+		ptFields[key] = value
+	}
+
+	for key, value := range r.tags {
+		ptTags[key] = value
+	}
+
+	measurement, ok := r.measurements[report]
+	if !ok {
+		measurement = r.metric
+	}
+
+	pt, err := client.NewPoint(measurement, ptTags, ptFields, ts)
+	if err != nil {
+		return
+	}
+
+	pts.AddPoint(pt)
+}
+
+// boolToInt converts a boolean to a number, for backends that only
+// support numeric values.
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+
+	return 0
 }
@@ -47,3 +47,43 @@ func TestLog(t *testing.T) {
 
 	assert.Equal(t, r.log, log)
 }
+
+func TestAggregation(t *testing.T) {
+	r := &InfluxReporter{}
+
+	Aggregation(AggregateTopic)(r)
+
+	assert.Equal(t, AggregateTopic, r.aggregation)
+}
+
+func TestMeasurements(t *testing.T) {
+	r := &InfluxReporter{}
+
+	Measurements(map[string]string{InfluxConsumerOffset: "lag"})(r)
+
+	assert.Equal(t, "lag", r.measurements[InfluxConsumerOffset])
+}
+
+func TestPolicies(t *testing.T) {
+	r := &InfluxReporter{}
+
+	Policies(map[string]string{InfluxMetric: "short"})(r)
+
+	assert.Equal(t, "short", r.policies[InfluxMetric])
+}
+
+func TestAsTags(t *testing.T) {
+	r := &InfluxReporter{asTags: map[string]bool{}}
+
+	AsTags("leaders")(r)
+
+	assert.True(t, r.asTags["leaders"])
+}
+
+func TestAsFields(t *testing.T) {
+	r := &InfluxReporter{asFields: map[string]bool{}}
+
+	AsFields("partition")(r)
+
+	assert.True(t, r.asFields["partition"])
+}
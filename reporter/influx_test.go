@@ -1,10 +1,12 @@
 package reporter_test
 
 import (
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/influxdata/influxdb/client/v2"
+	"github.com/msales/kage/metrics"
 	"github.com/msales/kage/reporter"
 	"github.com/msales/kage/store"
 	"github.com/msales/kage/testutil"
@@ -66,6 +68,28 @@ func TestInfluxReporter_ReportBrokerMetadata(t *testing.T) {
 
 }
 
+func TestInfluxReporter_ReportBrokerStatus(t *testing.T) {
+	c := new(mocks.MockInfluxClient)
+	c.On("Write", mock.AnythingOfType("*client.batchpoints")).Return(nil).Run(func(args mock.Arguments) {
+		bp := args.Get(0).(client.BatchPoints)
+		assert.Len(t, bp.Points(), 1)
+
+		pt := bp.Points()[0]
+		assert.Equal(t, map[string]string{"type": "BrokerStatus", "broker": "1"}, pt.Tags())
+		fields, _ := pt.Fields()
+		assert.Equal(t, map[string]interface{}{"connected": int64(0), "controller": int64(1), "leaders": int64(3), "replicas": int64(6)}, fields)
+	})
+
+	r := reporter.NewInfluxReporter(c,
+		reporter.Metric("kafka"),
+		reporter.Log(testutil.Logger),
+	)
+
+	statuses := &store.BrokerStatuses{{ID: 1, Connected: false, Controller: true, Leaders: 3, Replicas: 6}}
+	assert.NoError(t, r.ReportBrokerStatus(statuses))
+	c.AssertExpectations(t)
+}
+
 func TestInfluxReporter_ReportConsumerOffsets(t *testing.T) {
 	c := new(mocks.MockInfluxClient)
 	c.On("Write", mock.AnythingOfType("*client.batchpoints")).Return(nil).Run(func(args mock.Arguments) {
@@ -92,3 +116,189 @@ func TestInfluxReporter_ReportConsumerOffsets(t *testing.T) {
 	}
 	r.ReportConsumerOffsets(offsets)
 }
+
+func TestInfluxReporter_ReportTopicAggregates(t *testing.T) {
+	c := new(mocks.MockInfluxClient)
+	c.On("Write", mock.AnythingOfType("*client.batchpoints")).Return(nil).Run(func(args mock.Arguments) {
+		bp := args.Get(0).(client.BatchPoints)
+		assert.Len(t, bp.Points(), 1)
+
+		pt := bp.Points()[0]
+		assert.NotContains(t, pt.Tags(), "partition")
+		fields, _ := pt.Fields()
+		assert.Equal(t, map[string]interface{}{"partitions": int64(2), "lag": int64(300), "max_lag": int64(200)}, fields)
+	})
+
+	r := reporter.NewInfluxReporter(c,
+		reporter.Aggregation(reporter.AggregateTopic),
+		reporter.Metric("kafka"),
+		reporter.Log(testutil.Logger),
+	)
+
+	offsets := &store.ConsumerOffsets{
+		"foo": map[string][]*store.ConsumerOffset{
+			"test": {{Offset: 1000, Lag: 100}, {Offset: 900, Lag: 200}},
+		},
+	}
+	assert.NoError(t, r.ReportConsumerOffsets(offsets))
+	c.AssertExpectations(t)
+}
+
+func TestInfluxReporter_ReportGroupAggregates(t *testing.T) {
+	c := new(mocks.MockInfluxClient)
+	c.On("Write", mock.AnythingOfType("*client.batchpoints")).Return(nil).Run(func(args mock.Arguments) {
+		bp := args.Get(0).(client.BatchPoints)
+		assert.Len(t, bp.Points(), 1)
+
+		pt := bp.Points()[0]
+		assert.Equal(t, map[string]string{"type": "ConsumerOffset", "group": "foo"}, pt.Tags())
+	})
+
+	r := reporter.NewInfluxReporter(c,
+		reporter.Aggregation(reporter.AggregateGroup),
+		reporter.Metric("kafka"),
+		reporter.Log(testutil.Logger),
+	)
+
+	offsets := &store.ConsumerOffsets{
+		"foo": map[string][]*store.ConsumerOffset{
+			"test":  {{Offset: 1000, Lag: 100}},
+			"other": {{Offset: 900, Lag: 200}},
+		},
+	}
+	assert.NoError(t, r.ReportConsumerOffsets(offsets))
+	c.AssertExpectations(t)
+}
+
+func TestInfluxReporter_ReportOffsetRegressions(t *testing.T) {
+	c := new(mocks.MockInfluxClient)
+	c.On("Write", mock.AnythingOfType("*client.batchpoints")).Return(nil).Run(func(args mock.Arguments) {
+		bp := args.Get(0).(client.BatchPoints)
+		assert.Len(t, bp.Points(), 1)
+	})
+
+	r := reporter.NewInfluxReporter(c,
+		reporter.Tags(map[string]string{"test": "test"}),
+		reporter.Log(testutil.Logger),
+	)
+
+	regressions := &store.OffsetRegressions{
+		{
+			Kind:         store.RegressionRewind,
+			Group:        "foo",
+			Topic:        "test",
+			Partition:    0,
+			OldOffset:    1000,
+			NewOffset:    100,
+			NewestOffset: 1200,
+			Timestamp:    time.Now().Unix() * 1000,
+		},
+	}
+	r.ReportOffsetRegressions(regressions)
+}
+
+func TestInfluxReporter_ReportMetrics(t *testing.T) {
+	c := new(mocks.MockInfluxClient)
+	c.On("Write", mock.AnythingOfType("*client.batchpoints")).Return(nil).Run(func(args mock.Arguments) {
+		bp := args.Get(0).(client.BatchPoints)
+		assert.Len(t, bp.Points(), 1)
+
+		pt := bp.Points()[0]
+		assert.Equal(t, map[string]string{"type": "Metric", "name": "store.channel.depth", "test": "test"}, pt.Tags())
+	})
+
+	r := reporter.NewInfluxReporter(c,
+		reporter.Metric("kafka"),
+		reporter.Tags(map[string]string{"test": "test"}),
+		reporter.Log(testutil.Logger),
+	)
+
+	snapshot := &metrics.Snapshot{
+		{
+			Name:   "store.channel.depth",
+			Type:   metrics.TypeGauge,
+			Tags:   map[string]string{},
+			Values: map[string]float64{"value": 10},
+		},
+	}
+	assert.NoError(t, r.ReportMetrics(snapshot))
+
+	c.AssertExpectations(t)
+}
+
+func TestInfluxReporter_Measurements(t *testing.T) {
+	c := new(mocks.MockInfluxClient)
+	c.On("Write", mock.AnythingOfType("*client.batchpoints")).Return(nil).Run(func(args mock.Arguments) {
+		bp := args.Get(0).(client.BatchPoints)
+		assert.Equal(t, "short", bp.RetentionPolicy())
+		assert.Len(t, bp.Points(), 1)
+
+		pt := bp.Points()[0]
+		assert.Equal(t, "consumer_lag", pt.Name())
+		assert.Equal(t, time.Unix(1500000000, 0), pt.Time())
+	})
+
+	r := reporter.NewInfluxReporter(c,
+		reporter.Metric("kafka"),
+		reporter.Policy("long"),
+		reporter.Measurements(map[string]string{reporter.InfluxConsumerOffset: "consumer_lag"}),
+		reporter.Policies(map[string]string{reporter.InfluxConsumerOffset: "short"}),
+		reporter.Log(testutil.Logger),
+	)
+
+	offsets := &store.ConsumerOffsets{
+		"foo": map[string][]*store.ConsumerOffset{
+			"test": {{Offset: 1000, Lag: 100, Timestamp: 1500000000000}},
+		},
+	}
+	assert.NoError(t, r.ReportConsumerOffsets(offsets))
+	c.AssertExpectations(t)
+}
+
+func TestInfluxReporter_TagsAndFields(t *testing.T) {
+	c := new(mocks.MockInfluxClient)
+	c.On("Write", mock.AnythingOfType("*client.batchpoints")).Return(nil).Run(func(args mock.Arguments) {
+		bp := args.Get(0).(client.BatchPoints)
+		assert.Len(t, bp.Points(), 1)
+
+		pt := bp.Points()[0]
+		assert.Equal(t, map[string]string{"type": "BrokerMetadata", "topic": "test", "leaders": "1"}, pt.Tags())
+		fields, _ := pt.Fields()
+		assert.Equal(t, map[string]interface{}{"partition": int64(0), "replicas": int64(2), "isr": int64(1), "isr_diff": float64(1)}, fields)
+	})
+
+	r := reporter.NewInfluxReporter(c,
+		reporter.Metric("kafka"),
+		reporter.AsTags("leaders"),
+		reporter.AsFields("partition"),
+		reporter.Log(testutil.Logger),
+	)
+
+	metadata := &store.BrokerMetadata{
+		"test": []*store.Metadata{{Leader: 1, Replicas: []int32{1, 2}, Isr: []int32{1}}},
+	}
+	assert.NoError(t, r.ReportBrokerMetadata(metadata))
+	c.AssertExpectations(t)
+}
+
+func TestInfluxReporter_WriteError(t *testing.T) {
+	c := new(mocks.MockInfluxClient)
+	c.On("Write", mock.AnythingOfType("*client.batchpoints")).Return(errors.New("test error"))
+
+	r := reporter.NewInfluxReporter(c,
+		reporter.Log(testutil.Logger),
+	)
+
+	assert.Error(t, r.ReportBrokerOffsets(&store.BrokerOffsets{}))
+}
+
+func TestCheckInfluxReports(t *testing.T) {
+	err := reporter.CheckInfluxReports(map[string]string{
+		reporter.InfluxConsumerOffset: "lag",
+		reporter.InfluxMetric:         "kage",
+	})
+	assert.NoError(t, err)
+
+	err = reporter.CheckInfluxReports(map[string]string{"consumer_ofset": "lag"})
+	assert.EqualError(t, err, "influx: unknown report type \"consumer_ofset\"")
+}
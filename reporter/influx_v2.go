@@ -0,0 +1,156 @@
+package reporter
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+)
+
+// ErrQueryNotSupported is returned when querying through InfluxV2Client.
+var ErrQueryNotSupported = errors.New("influx: query is not supported by the v2 write client")
+
+// InfluxV2Config represents the configuration of an InfluxV2Client.
+type InfluxV2Config struct {
+	// Addr is the address of the InfluxDB server (e.g. "http://ip:port").
+	Addr string
+
+	// Token is the authentication token.
+	Token string
+
+	// Org is the organisation the bucket belongs to.
+	Org string
+
+	// Bucket is the bucket to write to. The database of the
+	// batch points is used when empty.
+	Bucket string
+
+	// Timeout is the time allowed for a request. Defaults to no timeout.
+	Timeout time.Duration
+}
+
+// InfluxV2Client represents an InfluxDB 2.x write API client. It
+// implements the InfluxDB 1.x client interface, so it can be used by
+// InfluxReporter, but only supports writes.
+type InfluxV2Client struct {
+	addr   string
+	token  string
+	org    string
+	bucket string
+
+	client *http.Client
+}
+
+// NewInfluxV2Client creates and returns a new InfluxV2Client.
+func NewInfluxV2Client(config InfluxV2Config) (*InfluxV2Client, error) {
+	u, err := url.Parse(config.Addr)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("influx: unsupported protocol scheme: %s", u.Scheme)
+	}
+
+	return &InfluxV2Client{
+		addr:   strings.TrimSuffix(config.Addr, "/"),
+		token:  config.Token,
+		org:    config.Org,
+		bucket: config.Bucket,
+		client: &http.Client{Timeout: config.Timeout},
+	}, nil
+}
+
+// Ping checks the status of the server.
+func (c *InfluxV2Client) Ping(timeout time.Duration) (time.Duration, string, error) {
+	now := time.Now()
+
+	hc := *c.client
+	hc.Timeout = timeout
+
+	resp, err := hc.Get(c.addr + "/ping")
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+
+	if err := influxV2Error(resp); err != nil {
+		return 0, "", err
+	}
+
+	return time.Since(now), resp.Header.Get("X-Influxdb-Version"), nil
+}
+
+// Write writes the batch points as line protocol to the bucket.
+func (c *InfluxV2Client) Write(bp client.BatchPoints) error {
+	var buf bytes.Buffer
+	for _, p := range bp.Points() {
+		if p == nil {
+			continue
+		}
+
+		buf.WriteString(p.PrecisionString(bp.Precision()))
+		buf.WriteByte('\n')
+	}
+
+	bucket := c.bucket
+	if bucket == "" {
+		bucket = bp.Database()
+	}
+
+	params := url.Values{}
+	params.Set("org", c.org)
+	params.Set("bucket", bucket)
+	if bp.Precision() != "" {
+		params.Set("precision", bp.Precision())
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.addr+"/api/v2/write?"+params.Encode(), &buf)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Token "+c.token)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return influxV2Error(resp)
+}
+
+// Query is not supported by the InfluxDB 2.x write client.
+func (c *InfluxV2Client) Query(q client.Query) (*client.Response, error) {
+	return nil, ErrQueryNotSupported
+}
+
+// Close releases the idle connections.
+func (c *InfluxV2Client) Close() error {
+	if t, ok := c.client.Transport.(*http.Transport); ok {
+		t.CloseIdleConnections()
+	}
+
+	return nil
+}
+
+// influxV2Error returns an error with the response body when the status
+// is not successful.
+func influxV2Error(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+		return nil
+	}
+
+	body, _ := ioutil.ReadAll(resp.Body)
+	return fmt.Errorf("influx: unexpected status %s: %s", resp.Status, strings.TrimSpace(string(body)))
+}
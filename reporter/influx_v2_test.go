@@ -0,0 +1,99 @@
+package reporter_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxdb/client/v2"
+	"github.com/msales/kage/reporter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewInfluxV2Client_InvalidScheme(t *testing.T) {
+	_, err := reporter.NewInfluxV2Client(reporter.InfluxV2Config{Addr: "udp://localhost:8089"})
+
+	assert.Error(t, err)
+}
+
+func TestInfluxV2Client_Write(t *testing.T) {
+	var req *http.Request
+	var body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		req, body = r, string(b)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	c, err := reporter.NewInfluxV2Client(reporter.InfluxV2Config{Addr: srv.URL, Token: "secret", Org: "msales", Bucket: "kafka"})
+	assert.NoError(t, err)
+
+	bp, _ := client.NewBatchPoints(client.BatchPointsConfig{Database: "ignored", Precision: "s"})
+	pt, _ := client.NewPoint("kafka", map[string]string{"topic": "test"}, map[string]interface{}{"lag": 10}, time.Unix(1500000000, 0))
+	bp.AddPoint(pt)
+
+	assert.NoError(t, c.Write(bp))
+	assert.Equal(t, "/api/v2/write", req.URL.Path)
+	assert.Equal(t, "msales", req.URL.Query().Get("org"))
+	assert.Equal(t, "kafka", req.URL.Query().Get("bucket"))
+	assert.Equal(t, "s", req.URL.Query().Get("precision"))
+	assert.Equal(t, "Token secret", req.Header.Get("Authorization"))
+	assert.Equal(t, "kafka,topic=test lag=10i 1500000000\n", body)
+}
+
+func TestInfluxV2Client_WriteDefaultsToDatabase(t *testing.T) {
+	var bucket string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bucket = r.URL.Query().Get("bucket")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	c, _ := reporter.NewInfluxV2Client(reporter.InfluxV2Config{Addr: srv.URL})
+
+	bp, _ := client.NewBatchPoints(client.BatchPointsConfig{Database: "kafka"})
+	assert.NoError(t, c.Write(bp))
+	assert.Equal(t, "kafka", bucket)
+}
+
+func TestInfluxV2Client_WriteError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"code":"unauthorized","message":"unauthorized access"}`))
+	}))
+	defer srv.Close()
+
+	c, _ := reporter.NewInfluxV2Client(reporter.InfluxV2Config{Addr: srv.URL})
+
+	bp, _ := client.NewBatchPoints(client.BatchPointsConfig{Database: "kafka"})
+	err := c.Write(bp)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unauthorized access")
+}
+
+func TestInfluxV2Client_Ping(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/ping", r.URL.Path)
+		w.Header().Set("X-Influxdb-Version", "2.7.1")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	c, _ := reporter.NewInfluxV2Client(reporter.InfluxV2Config{Addr: srv.URL})
+
+	_, version, err := c.Ping(time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, "2.7.1", version)
+}
+
+func TestInfluxV2Client_Query(t *testing.T) {
+	c, _ := reporter.NewInfluxV2Client(reporter.InfluxV2Config{Addr: "http://localhost:8086"})
+
+	_, err := c.Query(client.Query{})
+
+	assert.Equal(t, reporter.ErrQueryNotSupported, err)
+	assert.NoError(t, c.Close())
+}
@@ -0,0 +1,265 @@
+package reporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/msales/kage/metrics"
+	"github.com/msales/kage/store"
+	"github.com/msales/kage/utils"
+	"gopkg.in/inconshreveable/log15.v2"
+)
+
+// JSONReporterFunc represents a configuration function for JSONReporter.
+type JSONReporterFunc func(c *JSONReporter)
+
+// JSONLog configures the logger on a JSONReporter.
+func JSONLog(log log15.Logger) JSONReporterFunc {
+	return func(c *JSONReporter) {
+		c.log = log
+	}
+}
+
+// JSONReporter represents a JSON Lines reporter. It writes one JSON object
+// per partition, and each report in a single write.
+type JSONReporter struct {
+	w io.Writer
+
+	log log15.Logger
+}
+
+// NewJSONReporter creates and returns a new JSONReporter.
+func NewJSONReporter(w io.Writer, opts ...JSONReporterFunc) *JSONReporter {
+	r := &JSONReporter{
+		w: w,
+	}
+
+	for _, o := range opts {
+		o(r)
+	}
+
+	return r
+}
+
+type jsonBrokerOffset struct {
+	Type      string `json:"type"`
+	Timestamp int64  `json:"timestamp"`
+	Topic     string `json:"topic"`
+	Partition int    `json:"partition"`
+	Oldest    int64  `json:"oldest"`
+	Newest    int64  `json:"newest"`
+	Available int64  `json:"available"`
+}
+
+type jsonBrokerMetadata struct {
+	Type      string  `json:"type"`
+	Timestamp int64   `json:"timestamp"`
+	Topic     string  `json:"topic"`
+	Partition int     `json:"partition"`
+	Leader    int32   `json:"leader"`
+	Replicas  []int32 `json:"replicas"`
+	Isr       []int32 `json:"isr"`
+}
+
+type jsonBrokerStatus struct {
+	Type       string `json:"type"`
+	Timestamp  int64  `json:"timestamp"`
+	Broker     int32  `json:"broker"`
+	Connected  bool   `json:"connected"`
+	Controller bool   `json:"controller"`
+	Leaders    int    `json:"leaders"`
+	Replicas   int    `json:"replicas"`
+}
+
+type jsonConsumerOffset struct {
+	Type      string `json:"type"`
+	Timestamp int64  `json:"timestamp"`
+	Group     string `json:"group"`
+	Topic     string `json:"topic"`
+	Partition int    `json:"partition"`
+	Offset    int64  `json:"offset"`
+	Lag       int64  `json:"lag"`
+}
+
+type jsonOffsetRegression struct {
+	Type      string `json:"type"`
+	Timestamp int64  `json:"timestamp"`
+	Kind      string `json:"kind"`
+	Group     string `json:"group"`
+	Topic     string `json:"topic"`
+	Partition int32  `json:"partition"`
+	OldOffset int64  `json:"old_offset"`
+	NewOffset int64  `json:"new_offset"`
+	Newest    int64  `json:"newest"`
+}
+
+type jsonMetric struct {
+	Type       string             `json:"type"`
+	Timestamp  int64              `json:"timestamp"`
+	Name       string             `json:"name"`
+	MetricType string             `json:"metric_type"`
+	Tags       map[string]string  `json:"tags"`
+	Values     map[string]float64 `json:"values"`
+}
+
+// ReportBrokerOffsets reports a snapshot of the broker offsets.
+func (r JSONReporter) ReportBrokerOffsets(o *store.BrokerOffsets) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	now := utils.Millis(time.Now())
+
+	for topic, partitions := range *o {
+		for partition, offset := range partitions {
+			if offset == nil {
+				continue
+			}
+
+			enc.Encode(jsonBrokerOffset{
+				Type:      "broker_offset",
+				Timestamp: collectionTime(offset.Timestamp, now),
+				Topic:     topic,
+				Partition: partition,
+				Oldest:    offset.OldestOffset,
+				Newest:    offset.NewestOffset,
+				Available: offset.NewestOffset - offset.OldestOffset,
+			})
+		}
+	}
+
+	return r.write(buf.Bytes(), "offsets")
+}
+
+// ReportBrokerMetadata reports a snapshot of the broker metadata.
+func (r JSONReporter) ReportBrokerMetadata(m *store.BrokerMetadata) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	now := utils.Millis(time.Now())
+
+	for topic, partitions := range *m {
+		for partition, metadata := range partitions {
+			if metadata == nil {
+				continue
+			}
+
+			enc.Encode(jsonBrokerMetadata{
+				Type:      "broker_metadata",
+				Timestamp: collectionTime(metadata.Timestamp, now),
+				Topic:     topic,
+				Partition: partition,
+				Leader:    metadata.Leader,
+				Replicas:  metadata.Replicas,
+				Isr:       metadata.Isr,
+			})
+		}
+	}
+
+	return r.write(buf.Bytes(), "metadata")
+}
+
+// ReportBrokerStatus reports the status of the brokers.
+func (r JSONReporter) ReportBrokerStatus(s *store.BrokerStatuses) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	now := utils.Millis(time.Now())
+
+	for _, status := range *s {
+		enc.Encode(jsonBrokerStatus{
+			Type:       "broker_status",
+			Timestamp:  collectionTime(status.Timestamp, now),
+			Broker:     status.ID,
+			Connected:  status.Connected,
+			Controller: status.Controller,
+			Leaders:    status.Leaders,
+			Replicas:   status.Replicas,
+		})
+	}
+
+	return r.write(buf.Bytes(), "broker-status")
+}
+
+// ReportConsumerOffsets reports a snapshot of the consumer group offsets.
+func (r JSONReporter) ReportConsumerOffsets(o *store.ConsumerOffsets) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	now := utils.Millis(time.Now())
+
+	for group, topics := range *o {
+		for topic, partitions := range topics {
+			for partition, offset := range partitions {
+				if offset == nil {
+					continue
+				}
+
+				enc.Encode(jsonConsumerOffset{
+					Type:      "consumer_offset",
+					Timestamp: collectionTime(offset.Timestamp, now),
+					Group:     group,
+					Topic:     topic,
+					Partition: partition,
+					Offset:    offset.Offset,
+					Lag:       offset.Lag,
+				})
+			}
+		}
+	}
+
+	return r.write(buf.Bytes(), "consumer-offsets")
+}
+
+// ReportOffsetRegressions reports newly detected consumer offset regressions.
+func (r JSONReporter) ReportOffsetRegressions(o *store.OffsetRegressions) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	now := utils.Millis(time.Now())
+
+	for _, regression := range *o {
+		enc.Encode(jsonOffsetRegression{
+			Type:      "offset_regression",
+			Timestamp: collectionTime(regression.Timestamp, now),
+			Kind:      regression.Kind,
+			Group:     regression.Group,
+			Topic:     regression.Topic,
+			Partition: regression.Partition,
+			OldOffset: regression.OldOffset,
+			NewOffset: regression.NewOffset,
+			Newest:    regression.NewestOffset,
+		})
+	}
+
+	return r.write(buf.Bytes(), "offset-regressions")
+}
+
+// ReportMetrics reports a snapshot of the internal metrics.
+func (r JSONReporter) ReportMetrics(m *metrics.Snapshot) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	now := utils.Millis(time.Now())
+
+	for _, metric := range *m {
+		enc.Encode(jsonMetric{
+			Type:       "metric",
+			Timestamp:  now,
+			Name:       metric.Name,
+			MetricType: metric.Type,
+			Tags:       metric.Tags,
+			Values:     metric.Values,
+		})
+	}
+
+	return r.write(buf.Bytes(), "metrics")
+}
+
+func (r JSONReporter) write(data []byte, report string) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	if _, err := r.w.Write(data); err != nil {
+		r.log.Error("json: " + report + ":" + err.Error())
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,17 @@
+package reporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/inconshreveable/log15.v2"
+)
+
+func TestJSONLog(t *testing.T) {
+	log := log15.New()
+	r := &JSONReporter{}
+
+	JSONLog(log)(r)
+
+	assert.Equal(t, log, r.log)
+}
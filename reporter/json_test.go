@@ -0,0 +1,147 @@
+package reporter_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/msales/kage/metrics"
+	"github.com/msales/kage/reporter"
+	"github.com/msales/kage/store"
+	"github.com/msales/kage/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// decodeLines returns the decoded JSON lines, asserting each has a timestamp.
+func decodeLines(t *testing.T, data string) []map[string]interface{} {
+	values := []map[string]interface{}{}
+	for _, line := range strings.Split(strings.TrimSuffix(data, "\n"), "\n") {
+		v := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(line), &v); err != nil {
+			t.Fatal(err)
+		}
+		assert.NotZero(t, v["timestamp"])
+		delete(v, "timestamp")
+		values = append(values, v)
+	}
+
+	return values
+}
+
+func TestJSONReporter_ReportBrokerOffsets(t *testing.T) {
+	buf := &bytes.Buffer{}
+	r := reporter.NewJSONReporter(buf, reporter.JSONLog(testutil.Logger))
+
+	offsets := &store.BrokerOffsets{
+		"test": []*store.BrokerOffset{{OldestOffset: 10, NewestOffset: 1000, Timestamp: 1500000000000}, nil},
+	}
+	assert.NoError(t, r.ReportBrokerOffsets(offsets))
+
+	assert.Contains(t, buf.String(), `"timestamp":1500000000000`)
+	assert.Equal(t, []map[string]interface{}{
+		{"type": "broker_offset", "topic": "test", "partition": float64(0), "oldest": float64(10), "newest": float64(1000), "available": float64(990)},
+	}, decodeLines(t, buf.String()))
+}
+
+func TestJSONReporter_ReportBrokerMetadata(t *testing.T) {
+	buf := &bytes.Buffer{}
+	r := reporter.NewJSONReporter(buf, reporter.JSONLog(testutil.Logger))
+
+	metadata := &store.BrokerMetadata{
+		"test": []*store.Metadata{{Leader: 1, Replicas: []int32{1, 2}, Isr: []int32{1}}, nil},
+	}
+	assert.NoError(t, r.ReportBrokerMetadata(metadata))
+
+	assert.Equal(t, []map[string]interface{}{
+		{"type": "broker_metadata", "topic": "test", "partition": float64(0), "leader": float64(1), "replicas": []interface{}{float64(1), float64(2)}, "isr": []interface{}{float64(1)}},
+	}, decodeLines(t, buf.String()))
+}
+
+func TestJSONReporter_ReportBrokerStatus(t *testing.T) {
+	buf := &bytes.Buffer{}
+	r := reporter.NewJSONReporter(buf, reporter.JSONLog(testutil.Logger))
+
+	statuses := &store.BrokerStatuses{{ID: 1, Connected: true, Controller: true, Leaders: 3, Replicas: 6}}
+	assert.NoError(t, r.ReportBrokerStatus(statuses))
+
+	assert.Equal(t, []map[string]interface{}{
+		{"type": "broker_status", "broker": float64(1), "connected": true, "controller": true, "leaders": float64(3), "replicas": float64(6)},
+	}, decodeLines(t, buf.String()))
+}
+
+func TestJSONReporter_ReportConsumerOffsets(t *testing.T) {
+	buf := &bytes.Buffer{}
+	r := reporter.NewJSONReporter(buf, reporter.JSONLog(testutil.Logger))
+
+	offsets := &store.ConsumerOffsets{
+		"foo": map[string][]*store.ConsumerOffset{
+			"test": {{Offset: 990, Lag: 10}, {Offset: 500, Lag: 5}, nil},
+		},
+	}
+	assert.NoError(t, r.ReportConsumerOffsets(offsets))
+
+	assert.Equal(t, []map[string]interface{}{
+		{"type": "consumer_offset", "group": "foo", "topic": "test", "partition": float64(0), "offset": float64(990), "lag": float64(10)},
+		{"type": "consumer_offset", "group": "foo", "topic": "test", "partition": float64(1), "offset": float64(500), "lag": float64(5)},
+	}, decodeLines(t, buf.String()))
+}
+
+func TestJSONReporter_ReportOffsetRegressions(t *testing.T) {
+	buf := &bytes.Buffer{}
+	r := reporter.NewJSONReporter(buf, reporter.JSONLog(testutil.Logger))
+
+	regressions := &store.OffsetRegressions{
+		{Kind: store.RegressionRewind, Group: "foo", Topic: "test", Partition: 2, OldOffset: 100, NewOffset: 50, NewestOffset: 120, Timestamp: 1500000000000},
+	}
+	assert.NoError(t, r.ReportOffsetRegressions(regressions))
+
+	assert.Equal(t, []map[string]interface{}{
+		{"type": "offset_regression", "kind": "rewind", "group": "foo", "topic": "test", "partition": float64(2), "old_offset": float64(100), "new_offset": float64(50), "newest": float64(120)},
+	}, decodeLines(t, buf.String()))
+}
+
+func TestJSONReporter_ReportMetrics(t *testing.T) {
+	buf := &bytes.Buffer{}
+	r := reporter.NewJSONReporter(buf, reporter.JSONLog(testutil.Logger))
+
+	snapshot := &metrics.Snapshot{
+		{Name: "store.updates.dropped", Type: metrics.TypeCounter, Tags: map[string]string{"reason": "full"}, Values: map[string]float64{"count": 3}},
+	}
+	assert.NoError(t, r.ReportMetrics(snapshot))
+
+	assert.Equal(t, []map[string]interface{}{
+		{"type": "metric", "name": "store.updates.dropped", "metric_type": "counter", "tags": map[string]interface{}{"reason": "full"}, "values": map[string]interface{}{"count": float64(3)}},
+	}, decodeLines(t, buf.String()))
+}
+
+func TestJSONReporter_WritesReportOnce(t *testing.T) {
+	w := &countingWriter{}
+	r := reporter.NewJSONReporter(w, reporter.JSONLog(testutil.Logger))
+
+	offsets := &store.BrokerOffsets{
+		"test": []*store.BrokerOffset{{OldestOffset: 10, NewestOffset: 1000}, {OldestOffset: 10, NewestOffset: 1000}},
+	}
+	assert.NoError(t, r.ReportBrokerOffsets(offsets))
+	assert.NoError(t, r.ReportBrokerOffsets(&store.BrokerOffsets{}))
+
+	assert.Equal(t, 1, w.writes)
+}
+
+func TestJSONReporter_WriteError(t *testing.T) {
+	r := reporter.NewJSONReporter(errorWriter{}, reporter.JSONLog(testutil.Logger))
+
+	offsets := &store.BrokerOffsets{
+		"test": []*store.BrokerOffset{{OldestOffset: 10, NewestOffset: 1000}},
+	}
+	assert.Error(t, r.ReportBrokerOffsets(offsets))
+}
+
+type countingWriter struct {
+	writes int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	return len(p), nil
+}
@@ -0,0 +1,328 @@
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/msales/kage/metrics"
+	"github.com/msales/kage/store"
+	"github.com/msales/kage/utils"
+	"gopkg.in/inconshreveable/log15.v2"
+)
+
+// KafkaRecordVersion is the version of the records produced by KafkaReporter.
+const KafkaRecordVersion = 1
+
+// Kafka record types.
+const (
+	kafkaRecordBrokerOffsets    = "broker_offsets"
+	kafkaRecordBrokerMetadata   = "broker_metadata"
+	kafkaRecordBrokerStatus     = "broker_status"
+	kafkaRecordConsumerOffsets  = "consumer_offsets"
+	kafkaRecordOffsetRegression = "offset_regression"
+	kafkaRecordMetric           = "metric"
+)
+
+// KafkaReporterFunc represents a configuration function for KafkaReporter.
+type KafkaReporterFunc func(c *KafkaReporter)
+
+// KafkaTopic configures the topic on a KafkaReporter.
+func KafkaTopic(topic string) KafkaReporterFunc {
+	return func(c *KafkaReporter) {
+		c.topic = topic
+	}
+}
+
+// KafkaLog configures the logger on a KafkaReporter.
+func KafkaLog(log log15.Logger) KafkaReporterFunc {
+	return func(c *KafkaReporter) {
+		c.log = log
+	}
+}
+
+// KafkaReporter represents a Kafka reporter. It produces JSON records
+// keyed by topic, or by group for consumer group records.
+type KafkaReporter struct {
+	topic string
+
+	producer sarama.SyncProducer
+
+	log log15.Logger
+}
+
+// NewKafkaReporter creates and returns a new KafkaReporter.
+func NewKafkaReporter(producer sarama.SyncProducer, opts ...KafkaReporterFunc) *KafkaReporter {
+	r := &KafkaReporter{
+		producer: producer,
+	}
+
+	for _, o := range opts {
+		o(r)
+	}
+
+	return r
+}
+
+// kafkaRecord represents the common fields of a Kafka record.
+type kafkaRecord struct {
+	Version   int    `json:"version"`
+	Type      string `json:"type"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+type kafkaBrokerOffsets struct {
+	kafkaRecord
+	Topic      string                 `json:"topic"`
+	Partitions []kafkaBrokerPartition `json:"partitions"`
+}
+
+type kafkaBrokerPartition struct {
+	Partition int   `json:"partition"`
+	Oldest    int64 `json:"oldest"`
+	Newest    int64 `json:"newest"`
+	Available int64 `json:"available"`
+}
+
+type kafkaBrokerMetadata struct {
+	kafkaRecord
+	Topic      string                   `json:"topic"`
+	Partitions []kafkaPartitionMetadata `json:"partitions"`
+}
+
+type kafkaPartitionMetadata struct {
+	Partition int     `json:"partition"`
+	Leader    int32   `json:"leader"`
+	Replicas  []int32 `json:"replicas"`
+	Isr       []int32 `json:"isr"`
+}
+
+type kafkaBrokerStatus struct {
+	kafkaRecord
+	Broker     int32 `json:"broker"`
+	Connected  bool  `json:"connected"`
+	Controller bool  `json:"controller"`
+	Leaders    int   `json:"leaders"`
+	Replicas   int   `json:"replicas"`
+}
+
+type kafkaConsumerOffsets struct {
+	kafkaRecord
+	Group      string                   `json:"group"`
+	Topic      string                   `json:"topic"`
+	TotalLag   int64                    `json:"total_lag"`
+	Partitions []kafkaConsumerPartition `json:"partitions"`
+}
+
+type kafkaConsumerPartition struct {
+	Partition int   `json:"partition"`
+	Offset    int64 `json:"offset"`
+	Lag       int64 `json:"lag"`
+}
+
+type kafkaOffsetRegression struct {
+	kafkaRecord
+	Kind      string `json:"kind"`
+	Group     string `json:"group"`
+	Topic     string `json:"topic"`
+	Partition int32  `json:"partition"`
+	OldOffset int64  `json:"old_offset"`
+	NewOffset int64  `json:"new_offset"`
+	Newest    int64  `json:"newest"`
+}
+
+type kafkaMetric struct {
+	kafkaRecord
+	Name       string             `json:"name"`
+	MetricType string             `json:"metric_type"`
+	Tags       map[string]string  `json:"tags"`
+	Values     map[string]float64 `json:"values"`
+}
+
+// ReportBrokerOffsets reports a snapshot of the broker offsets.
+func (r KafkaReporter) ReportBrokerOffsets(o *store.BrokerOffsets) error {
+	rec := newKafkaRecord(kafkaRecordBrokerOffsets)
+	msgs := []*sarama.ProducerMessage{}
+
+	for topic, partitions := range *o {
+		v := kafkaBrokerOffsets{kafkaRecord: rec, Topic: topic, Partitions: []kafkaBrokerPartition{}}
+		var ts int64
+		for partition, offset := range partitions {
+			if offset == nil {
+				continue
+			}
+
+			ts = maxInt64(ts, offset.Timestamp)
+			v.Partitions = append(v.Partitions, kafkaBrokerPartition{
+				Partition: partition,
+				Oldest:    offset.OldestOffset,
+				Newest:    offset.NewestOffset,
+				Available: offset.NewestOffset - offset.OldestOffset,
+			})
+		}
+		v.Timestamp = collectionTime(ts, rec.Timestamp)
+
+		msgs = r.appendMessage(msgs, topic, v)
+	}
+
+	return r.send(msgs, "offsets")
+}
+
+// ReportBrokerMetadata reports a snapshot of the broker metadata.
+func (r KafkaReporter) ReportBrokerMetadata(m *store.BrokerMetadata) error {
+	rec := newKafkaRecord(kafkaRecordBrokerMetadata)
+	msgs := []*sarama.ProducerMessage{}
+
+	for topic, partitions := range *m {
+		v := kafkaBrokerMetadata{kafkaRecord: rec, Topic: topic, Partitions: []kafkaPartitionMetadata{}}
+		var ts int64
+		for partition, metadata := range partitions {
+			if metadata == nil {
+				continue
+			}
+
+			ts = maxInt64(ts, metadata.Timestamp)
+			v.Partitions = append(v.Partitions, kafkaPartitionMetadata{
+				Partition: partition,
+				Leader:    metadata.Leader,
+				Replicas:  metadata.Replicas,
+				Isr:       metadata.Isr,
+			})
+		}
+		v.Timestamp = collectionTime(ts, rec.Timestamp)
+
+		msgs = r.appendMessage(msgs, topic, v)
+	}
+
+	return r.send(msgs, "metadata")
+}
+
+// ReportBrokerStatus reports the status of the brokers.
+func (r KafkaReporter) ReportBrokerStatus(s *store.BrokerStatuses) error {
+	rec := newKafkaRecord(kafkaRecordBrokerStatus)
+	msgs := []*sarama.ProducerMessage{}
+
+	for _, status := range *s {
+		v := rec
+		v.Timestamp = collectionTime(status.Timestamp, rec.Timestamp)
+		msgs = r.appendMessage(msgs, fmt.Sprint(status.ID), kafkaBrokerStatus{
+			kafkaRecord: v,
+			Broker:      status.ID,
+			Connected:   status.Connected,
+			Controller:  status.Controller,
+			Leaders:     status.Leaders,
+			Replicas:    status.Replicas,
+		})
+	}
+
+	return r.send(msgs, "broker-status")
+}
+
+// ReportConsumerOffsets reports a snapshot of the consumer group offsets.
+func (r KafkaReporter) ReportConsumerOffsets(o *store.ConsumerOffsets) error {
+	rec := newKafkaRecord(kafkaRecordConsumerOffsets)
+	msgs := []*sarama.ProducerMessage{}
+
+	for group, topics := range *o {
+		for topic, partitions := range topics {
+			v := kafkaConsumerOffsets{kafkaRecord: rec, Group: group, Topic: topic, Partitions: []kafkaConsumerPartition{}}
+			var ts int64
+			for partition, offset := range partitions {
+				if offset == nil {
+					continue
+				}
+
+				ts = maxInt64(ts, offset.Timestamp)
+				v.TotalLag += offset.Lag
+				v.Partitions = append(v.Partitions, kafkaConsumerPartition{
+					Partition: partition,
+					Offset:    offset.Offset,
+					Lag:       offset.Lag,
+				})
+			}
+			v.Timestamp = collectionTime(ts, rec.Timestamp)
+
+			msgs = r.appendMessage(msgs, group, v)
+		}
+	}
+
+	return r.send(msgs, "consumer-offsets")
+}
+
+// ReportOffsetRegressions reports newly detected consumer offset regressions.
+func (r KafkaReporter) ReportOffsetRegressions(o *store.OffsetRegressions) error {
+	msgs := []*sarama.ProducerMessage{}
+
+	for _, regression := range *o {
+		rec := newKafkaRecord(kafkaRecordOffsetRegression)
+		rec.Timestamp = regression.Timestamp
+
+		msgs = r.appendMessage(msgs, regression.Group, kafkaOffsetRegression{
+			kafkaRecord: rec,
+			Kind:        regression.Kind,
+			Group:       regression.Group,
+			Topic:       regression.Topic,
+			Partition:   regression.Partition,
+			OldOffset:   regression.OldOffset,
+			NewOffset:   regression.NewOffset,
+			Newest:      regression.NewestOffset,
+		})
+	}
+
+	return r.send(msgs, "offset-regressions")
+}
+
+// ReportMetrics reports a snapshot of the internal metrics.
+func (r KafkaReporter) ReportMetrics(m *metrics.Snapshot) error {
+	rec := newKafkaRecord(kafkaRecordMetric)
+	msgs := []*sarama.ProducerMessage{}
+
+	for _, metric := range *m {
+		msgs = r.appendMessage(msgs, metric.Name, kafkaMetric{
+			kafkaRecord: rec,
+			Name:        metric.Name,
+			MetricType:  metric.Type,
+			Tags:        metric.Tags,
+			Values:      metric.Values,
+		})
+	}
+
+	return r.send(msgs, "metrics")
+}
+
+// appendMessage appends the JSON encoded record to the messages.
+func (r KafkaReporter) appendMessage(msgs []*sarama.ProducerMessage, key string, v interface{}) []*sarama.ProducerMessage {
+	data, err := json.Marshal(v)
+	if err != nil {
+		r.log.Error("kafka: cannot encode record:" + err.Error())
+		return msgs
+	}
+
+	return append(msgs, &sarama.ProducerMessage{
+		Topic: r.topic,
+		Key:   sarama.StringEncoder(key),
+		Value: sarama.ByteEncoder(data),
+	})
+}
+
+func (r KafkaReporter) send(msgs []*sarama.ProducerMessage, report string) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	if err := r.producer.SendMessages(msgs); err != nil {
+		r.log.Error("kafka: " + report + ":" + err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func newKafkaRecord(typ string) kafkaRecord {
+	return kafkaRecord{
+		Version:   KafkaRecordVersion,
+		Type:      typ,
+		Timestamp: utils.Millis(time.Now()),
+	}
+}
@@ -0,0 +1,25 @@
+package reporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/inconshreveable/log15.v2"
+)
+
+func TestKafkaTopic(t *testing.T) {
+	r := &KafkaReporter{}
+
+	KafkaTopic("kage")(r)
+
+	assert.Equal(t, "kage", r.topic)
+}
+
+func TestKafkaLog(t *testing.T) {
+	log := log15.New()
+	r := &KafkaReporter{}
+
+	KafkaLog(log)(r)
+
+	assert.Equal(t, log, r.log)
+}
@@ -0,0 +1,219 @@
+package reporter_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/msales/kage/metrics"
+	"github.com/msales/kage/reporter"
+	"github.com/msales/kage/store"
+	"github.com/msales/kage/testutil"
+	"github.com/msales/kage/testutil/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// decodeRecords returns the keys and decoded values of the produced messages.
+func decodeRecords(t *testing.T, msgs []*sarama.ProducerMessage) ([]string, []map[string]interface{}) {
+	keys := []string{}
+	values := []map[string]interface{}{}
+	for _, msg := range msgs {
+		assert.Equal(t, "kage", msg.Topic)
+
+		key, _ := msg.Key.Encode()
+		keys = append(keys, string(key))
+
+		data, _ := msg.Value.Encode()
+		v := map[string]interface{}{}
+		if err := json.Unmarshal(data, &v); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, float64(reporter.KafkaRecordVersion), v["version"])
+		assert.NotZero(t, v["timestamp"])
+		delete(v, "version")
+		delete(v, "timestamp")
+		values = append(values, v)
+	}
+
+	return keys, values
+}
+
+func TestKafkaReporter_ReportBrokerOffsets(t *testing.T) {
+	p := new(mocks.MockSyncProducer)
+	p.On("SendMessages", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		keys, values := decodeRecords(t, args.Get(0).([]*sarama.ProducerMessage))
+
+		assert.Equal(t, []string{"test"}, keys)
+		assert.Equal(t, map[string]interface{}{
+			"type":  "broker_offsets",
+			"topic": "test",
+			"partitions": []interface{}{
+				map[string]interface{}{"partition": float64(0), "oldest": float64(10), "newest": float64(1000), "available": float64(990)},
+			},
+		}, values[0])
+	})
+
+	r := reporter.NewKafkaReporter(p, reporter.KafkaTopic("kage"), reporter.KafkaLog(testutil.Logger))
+
+	offsets := &store.BrokerOffsets{
+		"test": []*store.BrokerOffset{{OldestOffset: 10, NewestOffset: 1000}, nil},
+	}
+	assert.NoError(t, r.ReportBrokerOffsets(offsets))
+	p.AssertExpectations(t)
+}
+
+func TestKafkaReporter_ReportBrokerMetadata(t *testing.T) {
+	p := new(mocks.MockSyncProducer)
+	p.On("SendMessages", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		keys, values := decodeRecords(t, args.Get(0).([]*sarama.ProducerMessage))
+
+		assert.Equal(t, []string{"test"}, keys)
+		assert.Equal(t, map[string]interface{}{
+			"type":  "broker_metadata",
+			"topic": "test",
+			"partitions": []interface{}{
+				map[string]interface{}{"partition": float64(0), "leader": float64(1), "replicas": []interface{}{float64(1), float64(2)}, "isr": []interface{}{float64(1)}},
+			},
+		}, values[0])
+	})
+
+	r := reporter.NewKafkaReporter(p, reporter.KafkaTopic("kage"), reporter.KafkaLog(testutil.Logger))
+
+	metadata := &store.BrokerMetadata{
+		"test": []*store.Metadata{{Leader: 1, Replicas: []int32{1, 2}, Isr: []int32{1}}},
+	}
+	assert.NoError(t, r.ReportBrokerMetadata(metadata))
+	p.AssertExpectations(t)
+}
+
+func TestKafkaReporter_ReportBrokerStatus(t *testing.T) {
+	p := new(mocks.MockSyncProducer)
+	p.On("SendMessages", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		keys, values := decodeRecords(t, args.Get(0).([]*sarama.ProducerMessage))
+
+		assert.Equal(t, []string{"1"}, keys)
+		assert.Equal(t, map[string]interface{}{
+			"type":       "broker_status",
+			"broker":     float64(1),
+			"connected":  true,
+			"controller": false,
+			"leaders":    float64(3),
+			"replicas":   float64(6),
+		}, values[0])
+	})
+
+	r := reporter.NewKafkaReporter(p, reporter.KafkaTopic("kage"), reporter.KafkaLog(testutil.Logger))
+
+	statuses := &store.BrokerStatuses{{ID: 1, Connected: true, Leaders: 3, Replicas: 6}}
+	assert.NoError(t, r.ReportBrokerStatus(statuses))
+	p.AssertExpectations(t)
+}
+
+func TestKafkaReporter_ReportConsumerOffsets(t *testing.T) {
+	p := new(mocks.MockSyncProducer)
+	p.On("SendMessages", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		keys, values := decodeRecords(t, args.Get(0).([]*sarama.ProducerMessage))
+
+		assert.Equal(t, []string{"foo"}, keys)
+		assert.Equal(t, map[string]interface{}{
+			"type":      "consumer_offsets",
+			"group":     "foo",
+			"topic":     "test",
+			"total_lag": float64(150),
+			"partitions": []interface{}{
+				map[string]interface{}{"partition": float64(0), "offset": float64(1000), "lag": float64(100)},
+				map[string]interface{}{"partition": float64(1), "offset": float64(500), "lag": float64(50)},
+			},
+		}, values[0])
+	})
+
+	r := reporter.NewKafkaReporter(p, reporter.KafkaTopic("kage"), reporter.KafkaLog(testutil.Logger))
+
+	offsets := &store.ConsumerOffsets{
+		"foo": map[string][]*store.ConsumerOffset{
+			"test": {{Offset: 1000, Lag: 100}, {Offset: 500, Lag: 50}},
+		},
+	}
+	assert.NoError(t, r.ReportConsumerOffsets(offsets))
+	p.AssertExpectations(t)
+}
+
+func TestKafkaReporter_ReportCollectionTime(t *testing.T) {
+	p := new(mocks.MockSyncProducer)
+	p.On("SendMessages", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		data, _ := args.Get(0).([]*sarama.ProducerMessage)[0].Value.Encode()
+		v := map[string]interface{}{}
+		if err := json.Unmarshal(data, &v); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, float64(1500000001000), v["timestamp"])
+	})
+
+	r := reporter.NewKafkaReporter(p, reporter.KafkaTopic("kage"), reporter.KafkaLog(testutil.Logger))
+
+	offsets := &store.ConsumerOffsets{
+		"foo": map[string][]*store.ConsumerOffset{
+			"test": {{Offset: 1000, Timestamp: 1500000000000}, {Offset: 500, Timestamp: 1500000001000}},
+		},
+	}
+	assert.NoError(t, r.ReportConsumerOffsets(offsets))
+	p.AssertExpectations(t)
+}
+
+func TestKafkaReporter_ReportOffsetRegressions(t *testing.T) {
+	p := new(mocks.MockSyncProducer)
+	p.On("SendMessages", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		keys, values := decodeRecords(t, args.Get(0).([]*sarama.ProducerMessage))
+
+		assert.Equal(t, []string{"foo"}, keys)
+		assert.Equal(t, "offset_regression", values[0]["type"])
+		assert.Equal(t, "rewind", values[0]["kind"])
+	})
+
+	r := reporter.NewKafkaReporter(p, reporter.KafkaTopic("kage"), reporter.KafkaLog(testutil.Logger))
+
+	regressions := &store.OffsetRegressions{
+		{Kind: store.RegressionRewind, Group: "foo", Topic: "test", Timestamp: 1500000000000},
+	}
+	assert.NoError(t, r.ReportOffsetRegressions(regressions))
+	p.AssertExpectations(t)
+}
+
+func TestKafkaReporter_ReportMetrics(t *testing.T) {
+	p := new(mocks.MockSyncProducer)
+	p.On("SendMessages", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		keys, values := decodeRecords(t, args.Get(0).([]*sarama.ProducerMessage))
+
+		assert.Equal(t, []string{"store.channel.depth"}, keys)
+		assert.Equal(t, map[string]interface{}{
+			"type":        "metric",
+			"name":        "store.channel.depth",
+			"metric_type": "gauge",
+			"tags":        map[string]interface{}{},
+			"values":      map[string]interface{}{"value": float64(3)},
+		}, values[0])
+	})
+
+	r := reporter.NewKafkaReporter(p, reporter.KafkaTopic("kage"), reporter.KafkaLog(testutil.Logger))
+
+	snapshot := &metrics.Snapshot{
+		{Name: "store.channel.depth", Type: metrics.TypeGauge, Tags: map[string]string{}, Values: map[string]float64{"value": 3}},
+	}
+	assert.NoError(t, r.ReportMetrics(snapshot))
+	p.AssertExpectations(t)
+}
+
+func TestKafkaReporter_SendError(t *testing.T) {
+	p := new(mocks.MockSyncProducer)
+	p.On("SendMessages", mock.Anything).Return(errors.New("test error"))
+
+	r := reporter.NewKafkaReporter(p, reporter.KafkaTopic("kage"), reporter.KafkaLog(testutil.Logger))
+
+	offsets := &store.BrokerOffsets{
+		"test": []*store.BrokerOffset{{OldestOffset: 10, NewestOffset: 1000}},
+	}
+	assert.Error(t, r.ReportBrokerOffsets(offsets))
+}
@@ -0,0 +1,253 @@
+package reporter
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/msales/kage/metrics"
+	"github.com/msales/kage/store"
+	"github.com/msales/kage/utils"
+	"gopkg.in/inconshreveable/log15.v2"
+)
+
+// DefaultOTLPEndpoint is the default OTLP/HTTP metrics endpoint of a local collector.
+const DefaultOTLPEndpoint = "http://localhost:4318/v1/metrics"
+
+// otlpScope is the instrumentation scope name of the reported metrics.
+const otlpScope = "github.com/msales/kage"
+
+// otlpTimeout is the time allowed to send a report to the collector.
+const otlpTimeout = 10 * time.Second
+
+// OTLPReporterFunc represents a configuration function for OTLPReporter.
+type OTLPReporterFunc func(c *OTLPReporter)
+
+// OTLPCluster configures the cluster name resource attribute on an OTLPReporter.
+func OTLPCluster(cluster string) OTLPReporterFunc {
+	return func(c *OTLPReporter) {
+		c.resource["kafka.cluster.name"] = cluster
+	}
+}
+
+// OTLPAttributes configures the additional resource attributes on an OTLPReporter.
+func OTLPAttributes(attrs map[string]string) OTLPReporterFunc {
+	return func(c *OTLPReporter) {
+		for key, value := range attrs {
+			c.resource[key] = value
+		}
+	}
+}
+
+// OTLPHeaders configures the additional request headers on an OTLPReporter.
+func OTLPHeaders(headers map[string]string) OTLPReporterFunc {
+	return func(c *OTLPReporter) {
+		c.headers = headers
+	}
+}
+
+// OTLPClient configures the http client on an OTLPReporter.
+func OTLPClient(client *http.Client) OTLPReporterFunc {
+	return func(c *OTLPReporter) {
+		c.client = client
+	}
+}
+
+// OTLPLog configures the logger on an OTLPReporter.
+func OTLPLog(log log15.Logger) OTLPReporterFunc {
+	return func(c *OTLPReporter) {
+		c.log = log
+	}
+}
+
+// OTLPReporter represents an OpenTelemetry reporter. It sends the reports
+// as OTLP gauges over OTLP/HTTP, encoded as protocol buffers.
+type OTLPReporter struct {
+	endpoint string
+	resource map[string]string
+	headers  map[string]string
+
+	client *http.Client
+
+	log log15.Logger
+}
+
+// NewOTLPReporter creates and returns a new OTLPReporter.
+func NewOTLPReporter(endpoint string, opts ...OTLPReporterFunc) *OTLPReporter {
+	r := &OTLPReporter{
+		endpoint: endpoint,
+		resource: map[string]string{"service.name": "kage"},
+		client:   &http.Client{Timeout: otlpTimeout},
+	}
+
+	for _, o := range opts {
+		o(r)
+	}
+
+	return r
+}
+
+// ReportBrokerOffsets reports a snapshot of the broker offsets.
+func (r OTLPReporter) ReportBrokerOffsets(o *store.BrokerOffsets) error {
+	m := newOTLPMetrics()
+	now := utils.Millis(time.Now())
+
+	for topic, partitions := range *o {
+		for partition, offset := range partitions {
+			if offset == nil {
+				continue
+			}
+
+			attrs := map[string]string{"topic": topic, "partition": fmt.Sprint(partition)}
+			ts := collectionTime(offset.Timestamp, now) * int64(time.Millisecond)
+			m.Gauge("kafka.broker.offset.oldest", "{offset}", attrs, offset.OldestOffset, ts)
+			m.Gauge("kafka.broker.offset.newest", "{offset}", attrs, offset.NewestOffset, ts)
+			m.Gauge("kafka.broker.offset.available", "{message}", attrs, offset.NewestOffset-offset.OldestOffset, ts)
+		}
+	}
+
+	return r.send(m, "offsets")
+}
+
+// ReportBrokerMetadata reports a snapshot of the broker metadata.
+func (r OTLPReporter) ReportBrokerMetadata(md *store.BrokerMetadata) error {
+	m := newOTLPMetrics()
+	now := utils.Millis(time.Now())
+
+	for topic, partitions := range *md {
+		for partition, metadata := range partitions {
+			if metadata == nil {
+				continue
+			}
+
+			leaders := int64(1)
+			if metadata.Leader < 0 {
+				leaders = 0
+			}
+
+			attrs := map[string]string{"topic": topic, "partition": fmt.Sprint(partition)}
+			ts := collectionTime(metadata.Timestamp, now) * int64(time.Millisecond)
+			m.Gauge("kafka.partition.leaders", "{broker}", attrs, leaders, ts)
+			m.Gauge("kafka.partition.replicas", "{broker}", attrs, int64(len(metadata.Replicas)), ts)
+			m.Gauge("kafka.partition.isr", "{broker}", attrs, int64(len(metadata.Isr)), ts)
+			m.Gauge("kafka.partition.isr_diff", "{broker}", attrs, int64(math.Abs(float64(len(metadata.Isr)-len(metadata.Replicas)))), ts)
+		}
+	}
+
+	return r.send(m, "metadata")
+}
+
+// ReportBrokerStatus reports the status of the brokers.
+func (r OTLPReporter) ReportBrokerStatus(s *store.BrokerStatuses) error {
+	m := newOTLPMetrics()
+	now := utils.Millis(time.Now())
+
+	for _, status := range *s {
+		attrs := map[string]string{"broker": fmt.Sprint(status.ID)}
+		ts := collectionTime(status.Timestamp, now) * int64(time.Millisecond)
+		m.Gauge("kafka.broker.connected", "", attrs, int64(boolToInt(status.Connected)), ts)
+		m.Gauge("kafka.broker.controller", "", attrs, int64(boolToInt(status.Controller)), ts)
+		m.Gauge("kafka.broker.leaders", "{partition}", attrs, int64(status.Leaders), ts)
+		m.Gauge("kafka.broker.replicas", "{replica}", attrs, int64(status.Replicas), ts)
+	}
+
+	return r.send(m, "broker-status")
+}
+
+// ReportConsumerOffsets reports a snapshot of the consumer group offsets.
+func (r OTLPReporter) ReportConsumerOffsets(o *store.ConsumerOffsets) error {
+	m := newOTLPMetrics()
+	now := utils.Millis(time.Now())
+
+	for group, topics := range *o {
+		for topic, partitions := range topics {
+			for partition, offset := range partitions {
+				if offset == nil {
+					continue
+				}
+
+				attrs := map[string]string{"group": group, "topic": topic, "partition": fmt.Sprint(partition)}
+				ts := collectionTime(offset.Timestamp, now) * int64(time.Millisecond)
+				m.Gauge("kafka.consumer.offset", "{offset}", attrs, offset.Offset, ts)
+				m.Gauge("kafka.consumer.lag", "{message}", attrs, offset.Lag, ts)
+			}
+		}
+	}
+
+	return r.send(m, "consumer-offsets")
+}
+
+// ReportOffsetRegressions reports newly detected consumer offset regressions.
+func (r OTLPReporter) ReportOffsetRegressions(o *store.OffsetRegressions) error {
+	m := newOTLPMetrics()
+	now := utils.Millis(time.Now())
+
+	for _, regression := range *o {
+		attrs := map[string]string{
+			"kind":      regression.Kind,
+			"group":     regression.Group,
+			"topic":     regression.Topic,
+			"partition": fmt.Sprint(regression.Partition),
+		}
+		ts := collectionTime(regression.Timestamp, now) * int64(time.Millisecond)
+		m.Gauge("kafka.consumer.regression", "{offset}", attrs, regression.NewOffset-regression.OldOffset, ts)
+	}
+
+	return r.send(m, "offset-regressions")
+}
+
+// ReportMetrics reports a snapshot of the internal metrics.
+func (r OTLPReporter) ReportMetrics(s *metrics.Snapshot) error {
+	m := newOTLPMetrics()
+	now := time.Now().UnixNano()
+
+	for _, metric := range *s {
+		for name, value := range metric.Values {
+			m.Gauge("kage."+metric.Name+"."+name, "", metric.Tags, value, now)
+		}
+	}
+
+	return r.send(m, "metrics")
+}
+
+func (r OTLPReporter) send(m *otlpMetrics, report string) error {
+	if m.Len() == 0 {
+		return nil
+	}
+
+	if err := r.post(m.Marshal(r.resource, otlpScope)); err != nil {
+		r.log.Error("otlp: " + report + ":" + err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (r OTLPReporter) post(body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, r.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	for key, value := range r.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package reporter
+
+import (
+	"encoding/binary"
+	"math"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/inconshreveable/log15.v2"
+)
+
+// protoFields decodes a protocol buffer message into its fields. Length
+// delimited fields are returned as bytes, and fixed64 fields as uint64.
+func protoFields(t *testing.T, data []byte) map[int][]interface{} {
+	fields := map[int][]interface{}{}
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		data = data[n:]
+
+		field, wire := int(key>>3), int(key&7)
+		switch wire {
+		case protoFixed64:
+			fields[field] = append(fields[field], binary.LittleEndian.Uint64(data))
+			data = data[8:]
+		case protoBytes:
+			l, n := binary.Uvarint(data)
+			fields[field] = append(fields[field], data[n:n+int(l)])
+			data = data[n+int(l):]
+		default:
+			t.Fatalf("unexpected wire type %d", wire)
+		}
+	}
+
+	return fields
+}
+
+// protoAttributes decodes the KeyValue fields of a message.
+func protoAttributes(t *testing.T, kvs []interface{}) map[string]string {
+	attrs := map[string]string{}
+	for _, kv := range kvs {
+		f := protoFields(t, kv.([]byte))
+		value := protoFields(t, f[otlpKeyValueValue][0].([]byte))
+		attrs[string(f[otlpKeyValueKey][0].([]byte))] = string(value[otlpAnyValueString][0].([]byte))
+	}
+
+	return attrs
+}
+
+func TestOTLPMetrics_Marshal(t *testing.T) {
+	m := newOTLPMetrics()
+	m.Gauge("kafka.consumer.lag", "{message}", map[string]string{"group": "foo", "partition": "0"}, int64(10), 1500000000000000000)
+	m.Gauge("kafka.consumer.lag", "{message}", map[string]string{"group": "foo", "partition": "1"}, int64(-5), 1500000000000000000)
+	m.Gauge("kage.monitor.collect.duration.mean", "", nil, 1.5, 1500000000000000000)
+
+	req := protoFields(t, m.Marshal(map[string]string{"service.name": "kage"}, "scope"))
+	assert.Len(t, req[otlpRequestResourceMetrics], 1)
+
+	rm := protoFields(t, req[otlpRequestResourceMetrics][0].([]byte))
+	res := protoFields(t, rm[otlpResourceMetricsResource][0].([]byte))
+	assert.Equal(t, map[string]string{"service.name": "kage"}, protoAttributes(t, res[otlpResourceAttributes]))
+
+	sm := protoFields(t, rm[otlpResourceMetricsScopeMetrics][0].([]byte))
+	scope := protoFields(t, sm[otlpScopeMetricsScope][0].([]byte))
+	assert.Equal(t, "scope", string(scope[otlpScopeName][0].([]byte)))
+	assert.Len(t, sm[otlpScopeMetricsMetrics], 2)
+
+	lag := protoFields(t, sm[otlpScopeMetricsMetrics][0].([]byte))
+	assert.Equal(t, "kafka.consumer.lag", string(lag[otlpMetricName][0].([]byte)))
+	assert.Equal(t, "{message}", string(lag[otlpMetricUnit][0].([]byte)))
+	gauge := protoFields(t, lag[otlpMetricGauge][0].([]byte))
+	assert.Len(t, gauge[otlpGaugeDataPoints], 2)
+
+	point := protoFields(t, gauge[otlpGaugeDataPoints][1].([]byte))
+	assert.Equal(t, uint64(1500000000000000000), point[otlpPointTime][0])
+	assert.Equal(t, int64(-5), int64(point[otlpPointAsInt][0].(uint64)))
+	assert.Equal(t, map[string]string{"group": "foo", "partition": "1"}, protoAttributes(t, point[otlpPointAttrs]))
+
+	mean := protoFields(t, sm[otlpScopeMetricsMetrics][1].([]byte))
+	assert.Nil(t, mean[otlpMetricUnit])
+	gauge = protoFields(t, mean[otlpMetricGauge][0].([]byte))
+	point = protoFields(t, gauge[otlpGaugeDataPoints][0].([]byte))
+	assert.Equal(t, 1.5, math.Float64frombits(point[otlpPointAsDouble][0].(uint64)))
+	assert.Nil(t, point[otlpPointAttrs])
+}
+
+func TestOTLPCluster(t *testing.T) {
+	r := &OTLPReporter{resource: map[string]string{}}
+
+	OTLPCluster("test")(r)
+
+	assert.Equal(t, map[string]string{"kafka.cluster.name": "test"}, r.resource)
+}
+
+func TestOTLPAttributes(t *testing.T) {
+	r := &OTLPReporter{resource: map[string]string{"service.name": "kage"}}
+
+	OTLPAttributes(map[string]string{"env": "prod"})(r)
+
+	assert.Equal(t, map[string]string{"service.name": "kage", "env": "prod"}, r.resource)
+}
+
+func TestOTLPHeaders(t *testing.T) {
+	headers := map[string]string{"Authorization": "Bearer test"}
+	r := &OTLPReporter{}
+
+	OTLPHeaders(headers)(r)
+
+	assert.Equal(t, headers, r.headers)
+}
+
+func TestOTLPClient(t *testing.T) {
+	client := &http.Client{}
+	r := &OTLPReporter{}
+
+	OTLPClient(client)(r)
+
+	assert.Equal(t, client, r.client)
+}
+
+func TestOTLPLog(t *testing.T) {
+	log := log15.New()
+	r := &OTLPReporter{}
+
+	OTLPLog(log)(r)
+
+	assert.Equal(t, log, r.log)
+}
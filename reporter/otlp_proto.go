@@ -0,0 +1,210 @@
+package reporter
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"sort"
+)
+
+// Protocol buffer wire types.
+const (
+	protoFixed64 = 1
+	protoBytes   = 2
+)
+
+// OTLP metrics field numbers, as defined by the opentelemetry-proto
+// collector/metrics/v1 and metrics/v1 messages.
+const (
+	otlpRequestResourceMetrics = 1
+
+	otlpResourceMetricsResource     = 1
+	otlpResourceMetricsScopeMetrics = 2
+
+	otlpResourceAttributes = 1
+
+	otlpScopeMetricsScope   = 1
+	otlpScopeMetricsMetrics = 2
+
+	otlpScopeName = 1
+
+	otlpMetricName  = 1
+	otlpMetricUnit  = 3
+	otlpMetricGauge = 5
+
+	otlpGaugeDataPoints = 1
+
+	otlpPointTime     = 3
+	otlpPointAsDouble = 4
+	otlpPointAsInt    = 6
+	otlpPointAttrs    = 7
+
+	otlpKeyValueKey   = 1
+	otlpKeyValueValue = 2
+
+	otlpAnyValueString = 1
+)
+
+// protoBuffer represents a protocol buffer message being encoded.
+type protoBuffer struct {
+	bytes.Buffer
+}
+
+func (b *protoBuffer) tag(field, wire int) {
+	b.varint(uint64(field<<3 | wire))
+}
+
+func (b *protoBuffer) varint(v uint64) {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], v)
+	b.Write(buf[:n])
+}
+
+func (b *protoBuffer) fixed64(field int, v uint64) {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], v)
+	b.tag(field, protoFixed64)
+	b.Write(buf[:])
+}
+
+// String encodes a string field. Empty strings are omitted.
+func (b *protoBuffer) String(field int, s string) {
+	if s == "" {
+		return
+	}
+
+	b.tag(field, protoBytes)
+	b.varint(uint64(len(s)))
+	b.WriteString(s)
+}
+
+// Message encodes an embedded message field.
+func (b *protoBuffer) Message(field int, m *protoBuffer) {
+	b.tag(field, protoBytes)
+	b.varint(uint64(m.Len()))
+	b.Write(m.Bytes())
+}
+
+// Double encodes a double field.
+func (b *protoBuffer) Double(field int, v float64) {
+	b.fixed64(field, math.Float64bits(v))
+}
+
+// Sfixed64 encodes a sfixed64 field.
+func (b *protoBuffer) Sfixed64(field int, v int64) {
+	b.fixed64(field, uint64(v))
+}
+
+// Fixed64 encodes a fixed64 field.
+func (b *protoBuffer) Fixed64(field int, v uint64) {
+	b.fixed64(field, v)
+}
+
+// otlpPoint represents a gauge data point.
+type otlpPoint struct {
+	attrs map[string]string
+	value interface{}
+	ts    int64
+}
+
+// otlpMetrics represents a set of gauges, in the order they were added.
+type otlpMetrics struct {
+	names  []string
+	units  map[string]string
+	points map[string][]otlpPoint
+}
+
+func newOTLPMetrics() *otlpMetrics {
+	return &otlpMetrics{
+		units:  map[string]string{},
+		points: map[string][]otlpPoint{},
+	}
+}
+
+// Gauge adds a data point to the named gauge. The value must be an int64
+// or a float64, and the timestamp is in nanoseconds.
+func (m *otlpMetrics) Gauge(name, unit string, attrs map[string]string, value interface{}, ts int64) {
+	if _, ok := m.points[name]; !ok {
+		m.names = append(m.names, name)
+		m.units[name] = unit
+	}
+
+	m.points[name] = append(m.points[name], otlpPoint{attrs: attrs, value: value, ts: ts})
+}
+
+// Len returns the number of gauges.
+func (m *otlpMetrics) Len() int {
+	return len(m.names)
+}
+
+// Marshal encodes the gauges as an ExportMetricsServiceRequest.
+func (m *otlpMetrics) Marshal(resource map[string]string, scope string) []byte {
+	res := &protoBuffer{}
+	encodeOTLPAttributes(res, otlpResourceAttributes, resource)
+
+	sc := &protoBuffer{}
+	sc.String(otlpScopeName, scope)
+
+	sm := &protoBuffer{}
+	sm.Message(otlpScopeMetricsScope, sc)
+	for _, name := range m.names {
+		gauge := &protoBuffer{}
+		for _, p := range m.points[name] {
+			gauge.Message(otlpGaugeDataPoints, encodeOTLPPoint(p))
+		}
+
+		metric := &protoBuffer{}
+		metric.String(otlpMetricName, name)
+		metric.String(otlpMetricUnit, m.units[name])
+		metric.Message(otlpMetricGauge, gauge)
+
+		sm.Message(otlpScopeMetricsMetrics, metric)
+	}
+
+	rm := &protoBuffer{}
+	rm.Message(otlpResourceMetricsResource, res)
+	rm.Message(otlpResourceMetricsScopeMetrics, sm)
+
+	req := &protoBuffer{}
+	req.Message(otlpRequestResourceMetrics, rm)
+
+	return req.Bytes()
+}
+
+func encodeOTLPPoint(p otlpPoint) *protoBuffer {
+	b := &protoBuffer{}
+	b.Fixed64(otlpPointTime, uint64(p.ts))
+
+	switch v := p.value.(type) {
+	case int64:
+		b.Sfixed64(otlpPointAsInt, v)
+	case float64:
+		b.Double(otlpPointAsDouble, v)
+	}
+
+	encodeOTLPAttributes(b, otlpPointAttrs, p.attrs)
+
+	return b
+}
+
+// encodeOTLPAttributes encodes the attributes as KeyValue fields, sorted by key.
+func encodeOTLPAttributes(b *protoBuffer, field int, attrs map[string]string) {
+	keys := make([]string, 0, len(attrs))
+	for key := range attrs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := &protoBuffer{}
+		value.tag(otlpAnyValueString, protoBytes)
+		value.varint(uint64(len(attrs[key])))
+		value.WriteString(attrs[key])
+
+		kv := &protoBuffer{}
+		kv.String(otlpKeyValueKey, key)
+		kv.Message(otlpKeyValueValue, value)
+
+		b.Message(field, kv)
+	}
+}
@@ -0,0 +1,157 @@
+package reporter_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/msales/kage/metrics"
+	"github.com/msales/kage/reporter"
+	"github.com/msales/kage/store"
+	"github.com/msales/kage/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// otlpCollector records the requests sent to it.
+type otlpCollector struct {
+	status  int
+	headers []http.Header
+	bodies  []string
+}
+
+func (c *otlpCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	c.headers = append(c.headers, r.Header)
+	c.bodies = append(c.bodies, string(body))
+
+	w.WriteHeader(c.status)
+}
+
+func newOTLPCollector(status int) (*otlpCollector, *httptest.Server) {
+	c := &otlpCollector{status: status}
+	return c, httptest.NewServer(c)
+}
+
+func TestOTLPReporter_ReportBrokerOffsets(t *testing.T) {
+	c, srv := newOTLPCollector(http.StatusOK)
+	defer srv.Close()
+
+	r := reporter.NewOTLPReporter(srv.URL,
+		reporter.OTLPCluster("main"),
+		reporter.OTLPHeaders(map[string]string{"Authorization": "Bearer test"}),
+		reporter.OTLPLog(testutil.Logger),
+	)
+
+	offsets := &store.BrokerOffsets{
+		"test": []*store.BrokerOffset{{OldestOffset: 10, NewestOffset: 1000}, nil},
+	}
+	assert.NoError(t, r.ReportBrokerOffsets(offsets))
+
+	assert.Len(t, c.bodies, 1)
+	assert.Equal(t, "application/x-protobuf", c.headers[0].Get("Content-Type"))
+	assert.Equal(t, "Bearer test", c.headers[0].Get("Authorization"))
+	assert.Contains(t, c.bodies[0], "kafka.broker.offset.available")
+	assert.Contains(t, c.bodies[0], "kafka.cluster.name")
+	assert.Contains(t, c.bodies[0], "main")
+}
+
+func TestOTLPReporter_ReportBrokerMetadata(t *testing.T) {
+	c, srv := newOTLPCollector(http.StatusOK)
+	defer srv.Close()
+
+	r := reporter.NewOTLPReporter(srv.URL, reporter.OTLPLog(testutil.Logger))
+
+	metadata := &store.BrokerMetadata{
+		"test": []*store.Metadata{{Leader: 1, Replicas: []int32{1, 2}, Isr: []int32{1}}, nil},
+	}
+	assert.NoError(t, r.ReportBrokerMetadata(metadata))
+
+	assert.Len(t, c.bodies, 1)
+	assert.Contains(t, c.bodies[0], "kafka.partition.isr_diff")
+}
+
+func TestOTLPReporter_ReportBrokerStatus(t *testing.T) {
+	c, srv := newOTLPCollector(http.StatusOK)
+	defer srv.Close()
+
+	r := reporter.NewOTLPReporter(srv.URL, reporter.OTLPLog(testutil.Logger))
+
+	statuses := &store.BrokerStatuses{{ID: 1, Connected: true, Leaders: 3, Replicas: 6}}
+	assert.NoError(t, r.ReportBrokerStatus(statuses))
+
+	assert.Len(t, c.bodies, 1)
+	assert.Contains(t, c.bodies[0], "kafka.broker.connected")
+	assert.Contains(t, c.bodies[0], "kafka.broker.replicas")
+}
+
+func TestOTLPReporter_ReportConsumerOffsets(t *testing.T) {
+	c, srv := newOTLPCollector(http.StatusOK)
+	defer srv.Close()
+
+	r := reporter.NewOTLPReporter(srv.URL, reporter.OTLPLog(testutil.Logger))
+
+	offsets := &store.ConsumerOffsets{
+		"foo": map[string][]*store.ConsumerOffset{
+			"test": {{Offset: 990, Lag: 10}, nil},
+		},
+	}
+	assert.NoError(t, r.ReportConsumerOffsets(offsets))
+
+	assert.Len(t, c.bodies, 1)
+	assert.Contains(t, c.bodies[0], "kafka.consumer.lag")
+	assert.Contains(t, c.bodies[0], "foo")
+}
+
+func TestOTLPReporter_ReportOffsetRegressions(t *testing.T) {
+	c, srv := newOTLPCollector(http.StatusOK)
+	defer srv.Close()
+
+	r := reporter.NewOTLPReporter(srv.URL, reporter.OTLPLog(testutil.Logger))
+
+	regressions := &store.OffsetRegressions{
+		{Kind: store.RegressionRewind, Group: "foo", Topic: "test", Partition: 2, OldOffset: 100, NewOffset: 50, Timestamp: 1500000000000},
+	}
+	assert.NoError(t, r.ReportOffsetRegressions(regressions))
+
+	assert.Len(t, c.bodies, 1)
+	assert.Contains(t, c.bodies[0], "kafka.consumer.regression")
+}
+
+func TestOTLPReporter_ReportMetrics(t *testing.T) {
+	c, srv := newOTLPCollector(http.StatusOK)
+	defer srv.Close()
+
+	r := reporter.NewOTLPReporter(srv.URL, reporter.OTLPLog(testutil.Logger))
+
+	snapshot := &metrics.Snapshot{
+		{Name: "store.updates.dropped", Type: metrics.TypeCounter, Values: map[string]float64{"count": 3}},
+	}
+	assert.NoError(t, r.ReportMetrics(snapshot))
+
+	assert.Len(t, c.bodies, 1)
+	assert.Contains(t, c.bodies[0], "kage.store.updates.dropped.count")
+}
+
+func TestOTLPReporter_SkipsEmptyReports(t *testing.T) {
+	c, srv := newOTLPCollector(http.StatusOK)
+	defer srv.Close()
+
+	r := reporter.NewOTLPReporter(srv.URL, reporter.OTLPLog(testutil.Logger))
+
+	assert.NoError(t, r.ReportBrokerOffsets(&store.BrokerOffsets{}))
+
+	assert.Len(t, c.bodies, 0)
+}
+
+func TestOTLPReporter_StatusError(t *testing.T) {
+	_, srv := newOTLPCollector(http.StatusBadRequest)
+	defer srv.Close()
+
+	r := reporter.NewOTLPReporter(srv.URL, reporter.OTLPLog(testutil.Logger))
+
+	offsets := &store.BrokerOffsets{
+		"test": []*store.BrokerOffset{{OldestOffset: 10, NewestOffset: 1000}},
+	}
+	assert.Error(t, r.ReportBrokerOffsets(offsets))
+}
@@ -0,0 +1,148 @@
+package reporter
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"gopkg.in/inconshreveable/log15.v2"
+)
+
+// rotatedTimeFormat is the time format appended to the name of rotated files.
+const rotatedTimeFormat = "20060102T150405.000"
+
+// RotatingFileFunc represents a configuration function for RotatingFile.
+type RotatingFileFunc func(f *RotatingFile)
+
+// RotatingFileLog configures the logger on a RotatingFile.
+func RotatingFileLog(log log15.Logger) RotatingFileFunc {
+	return func(f *RotatingFile) {
+		f.log = log
+	}
+}
+
+// RotatingFile represents a file that is rotated when it grows over a maximum
+// size or gets older than a maximum age. Rotated files are renamed with the
+// time of the rotation appended to the file name.
+type RotatingFile struct {
+	path    string
+	maxSize int64
+	maxAge  time.Duration
+
+	lock   sync.Mutex
+	file   *os.File
+	size   int64
+	opened time.Time
+
+	log log15.Logger
+}
+
+// NewRotatingFile opens and returns a new RotatingFile. A zero maxSize or
+// maxAge disables the size or time based rotation.
+func NewRotatingFile(path string, maxSize int64, maxAge time.Duration, opts ...RotatingFileFunc) (*RotatingFile, error) {
+	f := &RotatingFile{
+		path:    path,
+		maxSize: maxSize,
+		maxAge:  maxAge,
+		log:     log15.New(),
+	}
+
+	for _, o := range opts {
+		o(f)
+	}
+
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// Write writes the data to the file, rotating the file beforehand if the
+// data would grow it over the maximum size, or the file is too old. When
+// the file cannot be rotated, the data is written to the current file, and
+// the rotation is tried again once it grows by the maximum size or age.
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.file != nil && f.shouldRotate(int64(len(p))) {
+		if err := f.rotate(); err != nil {
+			f.log.Error("rotate: cannot rotate " + f.path + ": " + err.Error())
+		}
+	}
+
+	if f.file == nil {
+		if err := f.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+
+	return n, err
+}
+
+// Close closes the file.
+func (f *RotatingFile) Close() error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+
+	return f.file.Close()
+}
+
+func (f *RotatingFile) shouldRotate(n int64) bool {
+	if f.size == 0 {
+		return false
+	}
+
+	if f.maxSize > 0 && f.size+n > f.maxSize {
+		return true
+	}
+
+	return f.maxAge > 0 && time.Since(f.opened) >= f.maxAge
+}
+
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	f.file = file
+	f.size = info.Size()
+	f.opened = time.Now()
+
+	return nil
+}
+
+// rotate closes, renames and reopens the file. The original file is
+// reopened when it cannot be renamed.
+func (f *RotatingFile) rotate() error {
+	f.file.Close()
+	f.file = nil
+
+	renameErr := os.Rename(f.path, f.path+"."+time.Now().Format(rotatedTimeFormat))
+	if err := f.open(); err != nil {
+		return err
+	}
+
+	if renameErr != nil {
+		// Count the size from now on, so the rotation is not tried on every write
+		f.size = 0
+		return renameErr
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+package reporter_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/msales/kage/reporter"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/inconshreveable/log15.v2"
+)
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "kage")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return dir
+}
+
+func TestRotatingFile_Write(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "kage.log")
+
+	f, err := reporter.NewRotatingFile(path, 0, 0)
+	assert.NoError(t, err)
+
+	f.Write([]byte("foo\n"))
+	f.Write([]byte("bar\n"))
+	assert.NoError(t, f.Close())
+
+	data, _ := ioutil.ReadFile(path)
+	assert.Equal(t, "foo\nbar\n", string(data))
+}
+
+func TestRotatingFile_Appends(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "kage.log")
+	ioutil.WriteFile(path, []byte("foo\n"), 0644)
+
+	f, err := reporter.NewRotatingFile(path, 0, 0)
+	assert.NoError(t, err)
+
+	f.Write([]byte("bar\n"))
+	f.Close()
+
+	data, _ := ioutil.ReadFile(path)
+	assert.Equal(t, "foo\nbar\n", string(data))
+}
+
+func TestRotatingFile_RotatesOnSize(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "kage.log")
+
+	f, err := reporter.NewRotatingFile(path, 6, 0)
+	assert.NoError(t, err)
+
+	f.Write([]byte("foo\n"))
+	f.Write([]byte("bar\n"))
+	f.Close()
+
+	data, _ := ioutil.ReadFile(path)
+	assert.Equal(t, "bar\n", string(data))
+
+	rotated, _ := filepath.Glob(path + ".*")
+	assert.Len(t, rotated, 1)
+	data, _ = ioutil.ReadFile(rotated[0])
+	assert.Equal(t, "foo\n", string(data))
+}
+
+func TestRotatingFile_RotatesOnAge(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "kage.log")
+
+	f, err := reporter.NewRotatingFile(path, 0, 10*time.Millisecond)
+	assert.NoError(t, err)
+
+	f.Write([]byte("foo\n"))
+	time.Sleep(20 * time.Millisecond)
+	f.Write([]byte("bar\n"))
+	f.Close()
+
+	data, _ := ioutil.ReadFile(path)
+	assert.Equal(t, "bar\n", string(data))
+
+	rotated, _ := filepath.Glob(path + ".*")
+	assert.Len(t, rotated, 1)
+}
+
+func TestNewRotatingFile_Error(t *testing.T) {
+	_, err := reporter.NewRotatingFile("/does/not/exist/kage.log", 0, 0)
+
+	assert.Error(t, err)
+}
+
+func TestRotatingFile_RecoversFromFailedRotation(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	logDir := filepath.Join(dir, "logs")
+	os.Mkdir(logDir, 0755)
+	path := filepath.Join(logDir, "kage.log")
+
+	f, err := reporter.NewRotatingFile(path, 5, 0)
+	assert.NoError(t, err)
+	f.Write([]byte("foo\n"))
+
+	os.RemoveAll(logDir)
+	_, err = f.Write([]byte("bar\n"))
+	assert.Error(t, err)
+
+	os.Mkdir(logDir, 0755)
+	_, err = f.Write([]byte("baz\n"))
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+
+	data, _ := ioutil.ReadFile(path)
+	assert.Equal(t, "baz\n", string(data))
+}
+
+func TestRotatingFile_LogsFailedRotation(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "kage.log")
+
+	logs := []string{}
+	logger := log15.New()
+	logger.SetHandler(log15.FuncHandler(func(r *log15.Record) error {
+		logs = append(logs, r.Msg)
+		return nil
+	}))
+
+	f, err := reporter.NewRotatingFile(path, 5, 0, reporter.RotatingFileLog(logger))
+	assert.NoError(t, err)
+	f.Write([]byte("foo\n"))
+
+	// The file cannot be renamed once it was removed
+	os.Remove(path)
+	_, err = f.Write([]byte("bar\n"))
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+
+	assert.Len(t, logs, 1)
+	data, _ := ioutil.ReadFile(path)
+	assert.Equal(t, "bar\n", string(data))
+}
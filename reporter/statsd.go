@@ -0,0 +1,280 @@
+package reporter
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/msales/kage/metrics"
+	"github.com/msales/kage/store"
+	"gopkg.in/inconshreveable/log15.v2"
+)
+
+// maxPacketSize is the maximum size of a StatsD packet.
+const maxPacketSize = 1432
+
+// StatsdReporterFunc represents a configuration function for StatsdReporter.
+type StatsdReporterFunc func(c *StatsdReporter)
+
+// StatsdPrefix configures the metric prefix on a StatsdReporter.
+func StatsdPrefix(prefix string) StatsdReporterFunc {
+	return func(c *StatsdReporter) {
+		c.prefix = prefix
+	}
+}
+
+// StatsdTags configures the DogStatsD tags on a StatsdReporter.
+func StatsdTags(tags map[string]string) StatsdReporterFunc {
+	return func(c *StatsdReporter) {
+		c.tags = tags
+	}
+}
+
+// StatsdLog configures the logger on a StatsdReporter.
+func StatsdLog(log log15.Logger) StatsdReporterFunc {
+	return func(c *StatsdReporter) {
+		c.log = log
+	}
+}
+
+// StatsdReporter represents a StatsD reporter. Each write to the
+// writer is sent as a single packet.
+type StatsdReporter struct {
+	prefix string
+	tags   map[string]string
+
+	w io.Writer
+
+	log log15.Logger
+}
+
+// NewStatsdReporter creates and returns a new StatsdReporter.
+func NewStatsdReporter(w io.Writer, opts ...StatsdReporterFunc) *StatsdReporter {
+	r := &StatsdReporter{
+		w: w,
+	}
+
+	for _, o := range opts {
+		o(r)
+	}
+
+	return r
+}
+
+// ReportBrokerOffsets reports a snapshot of the broker offsets.
+func (r StatsdReporter) ReportBrokerOffsets(o *store.BrokerOffsets) error {
+	b := r.newBatch()
+
+	for topic, partitions := range *o {
+		for partition, offset := range partitions {
+			if offset == nil {
+				continue
+			}
+
+			path := []string{"broker", topic, fmt.Sprint(partition)}
+			b.Gauge(path, "oldest", float64(offset.OldestOffset))
+			b.Gauge(path, "newest", float64(offset.NewestOffset))
+			b.Gauge(path, "available", float64(offset.NewestOffset-offset.OldestOffset))
+		}
+	}
+
+	return r.flush(b, "offsets")
+}
+
+// ReportBrokerMetadata reports a snapshot of the broker metadata.
+func (r StatsdReporter) ReportBrokerMetadata(m *store.BrokerMetadata) error {
+	b := r.newBatch()
+
+	for topic, partitions := range *m {
+		for partition, metadata := range partitions {
+			if metadata == nil {
+				continue
+			}
+
+			leaders := 1
+			if metadata.Leader < 0 {
+				leaders = 0
+			}
+
+			path := []string{"metadata", topic, fmt.Sprint(partition)}
+			b.Gauge(path, "leaders", float64(leaders))
+			b.Gauge(path, "replicas", float64(len(metadata.Replicas)))
+			b.Gauge(path, "isr", float64(len(metadata.Isr)))
+			b.Gauge(path, "isr_diff", math.Abs(float64(len(metadata.Isr)-len(metadata.Replicas))))
+		}
+	}
+
+	return r.flush(b, "metadata")
+}
+
+// ReportBrokerStatus reports the status of the brokers.
+func (r StatsdReporter) ReportBrokerStatus(s *store.BrokerStatuses) error {
+	b := r.newBatch()
+
+	for _, status := range *s {
+		path := []string{"brokers", fmt.Sprint(status.ID)}
+		b.Gauge(path, "connected", float64(boolToInt(status.Connected)))
+		b.Gauge(path, "controller", float64(boolToInt(status.Controller)))
+		b.Gauge(path, "leaders", float64(status.Leaders))
+		b.Gauge(path, "replicas", float64(status.Replicas))
+	}
+
+	return r.flush(b, "broker-status")
+}
+
+// ReportConsumerOffsets reports a snapshot of the consumer group offsets.
+func (r StatsdReporter) ReportConsumerOffsets(o *store.ConsumerOffsets) error {
+	b := r.newBatch()
+
+	for group, topics := range *o {
+		for topic, partitions := range topics {
+			for partition, offset := range partitions {
+				if offset == nil {
+					continue
+				}
+
+				path := []string{"consumer", group, topic, fmt.Sprint(partition)}
+				b.Gauge(path, "offset", float64(offset.Offset))
+				b.Gauge(path, "lag", float64(offset.Lag))
+			}
+		}
+	}
+
+	return r.flush(b, "consumer-offsets")
+}
+
+// ReportOffsetRegressions reports newly detected consumer offset regressions.
+func (r StatsdReporter) ReportOffsetRegressions(o *store.OffsetRegressions) error {
+	b := r.newBatch()
+
+	for _, regression := range *o {
+		path := []string{"regression", regression.Group, regression.Topic, fmt.Sprint(regression.Partition)}
+		b.Count(path, regression.Kind, 1)
+	}
+
+	return r.flush(b, "offset-regressions")
+}
+
+// ReportMetrics reports a snapshot of the internal metrics.
+func (r StatsdReporter) ReportMetrics(m *metrics.Snapshot) error {
+	b := r.newBatch()
+
+	for _, metric := range *m {
+		keys := make([]string, 0, len(metric.Tags))
+		for key := range metric.Tags {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		path := []string{"kage", metric.Name}
+		for _, key := range keys {
+			path = append(path, metric.Tags[key])
+		}
+
+		for name, value := range metric.Values {
+			b.Gauge(path, name, value)
+		}
+	}
+
+	return r.flush(b, "metrics")
+}
+
+func (r StatsdReporter) newBatch() *statsdBatch {
+	return &statsdBatch{
+		prefix: r.prefix,
+		tags:   formatDogStatsdTags(r.tags),
+	}
+}
+
+func (r StatsdReporter) flush(b *statsdBatch, report string) error {
+	for _, packet := range b.packets() {
+		if _, err := r.w.Write(packet); err != nil {
+			r.log.Error("statsd: " + report + ":" + err.Error())
+			return err
+		}
+	}
+
+	return nil
+}
+
+// statsdBatch represents a set of StatsD lines split into packets.
+type statsdBatch struct {
+	prefix string
+	tags   string
+
+	buf  bytes.Buffer
+	pkts [][]byte
+}
+
+// Gauge adds a gauge to the batch.
+func (b *statsdBatch) Gauge(path []string, name string, value float64) {
+	b.add(path, name, fmt.Sprint(value), "g")
+}
+
+// Count adds a counter to the batch.
+func (b *statsdBatch) Count(path []string, name string, value int64) {
+	b.add(path, name, fmt.Sprint(value), "c")
+}
+
+func (b *statsdBatch) add(path []string, name, value, typ string) {
+	parts := make([]string, 0, len(path)+2)
+	if b.prefix != "" {
+		parts = append(parts, b.prefix)
+	}
+	for _, p := range path {
+		parts = append(parts, escapeStatsd(p))
+	}
+	parts = append(parts, name)
+
+	line := strings.Join(parts, ".") + ":" + value + "|" + typ + b.tags
+
+	if b.buf.Len() > 0 && b.buf.Len()+1+len(line) > maxPacketSize {
+		b.pkts = append(b.pkts, b.buf.Bytes())
+		b.buf = bytes.Buffer{}
+	}
+
+	if b.buf.Len() > 0 {
+		b.buf.WriteByte('\n')
+	}
+	b.buf.WriteString(line)
+}
+
+func (b *statsdBatch) packets() [][]byte {
+	if b.buf.Len() > 0 {
+		b.pkts = append(b.pkts, b.buf.Bytes())
+		b.buf = bytes.Buffer{}
+	}
+
+	return b.pkts
+}
+
+// statsdReplacer replaces the characters with a meaning in the StatsD protocol.
+var statsdReplacer = strings.NewReplacer(".", "_", ":", "_", "|", "_", "@", "_", "#", "_", ",", "_", " ", "_", "\n", "_")
+
+// escapeStatsd escapes a StatsD metric name part.
+func escapeStatsd(s string) string {
+	return statsdReplacer.Replace(s)
+}
+
+// formatDogStatsdTags formats the tags as a DogStatsD tag suffix.
+func formatDogStatsdTags(tags map[string]string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+
+	pairs := make([]string, 0, len(tags))
+	for key, value := range tags {
+		if value == "" {
+			pairs = append(pairs, key)
+			continue
+		}
+
+		pairs = append(pairs, key+":"+value)
+	}
+	sort.Strings(pairs)
+
+	return "|#" + strings.Join(pairs, ",")
+}
@@ -0,0 +1,47 @@
+package reporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/inconshreveable/log15.v2"
+)
+
+func TestStatsdPrefix(t *testing.T) {
+	r := &StatsdReporter{}
+
+	StatsdPrefix("kafka")(r)
+
+	assert.Equal(t, "kafka", r.prefix)
+}
+
+func TestStatsdTags(t *testing.T) {
+	r := &StatsdReporter{}
+
+	StatsdTags(map[string]string{"foo": "bar"})(r)
+
+	assert.Equal(t, "bar", r.tags["foo"])
+}
+
+func TestStatsdLog(t *testing.T) {
+	log := log15.New()
+	r := &StatsdReporter{}
+
+	StatsdLog(log)(r)
+
+	assert.Equal(t, log, r.log)
+}
+
+func TestStatsdBatch_Packets(t *testing.T) {
+	b := &statsdBatch{prefix: "kafka"}
+	for i := 0; i < 100; i++ {
+		b.Gauge([]string{"broker", "test", "0"}, "newest", float64(i))
+	}
+
+	packets := b.packets()
+
+	assert.True(t, len(packets) > 1)
+	for _, p := range packets {
+		assert.True(t, len(p) <= maxPacketSize)
+	}
+}
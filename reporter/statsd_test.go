@@ -0,0 +1,131 @@
+package reporter_test
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/msales/kage/metrics"
+	"github.com/msales/kage/reporter"
+	"github.com/msales/kage/store"
+	"github.com/msales/kage/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func statsdLines(buf *bytes.Buffer) []string {
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	sort.Strings(lines)
+
+	return lines
+}
+
+func TestStatsdReporter_ReportBrokerOffsets(t *testing.T) {
+	buf := bytes.NewBuffer([]byte{})
+	r := reporter.NewStatsdReporter(buf,
+		reporter.StatsdPrefix("kafka"),
+		reporter.StatsdTags(map[string]string{"env": "test"}),
+		reporter.StatsdLog(testutil.Logger),
+	)
+
+	offsets := &store.BrokerOffsets{
+		"test.topic": []*store.BrokerOffset{{OldestOffset: 10, NewestOffset: 1000}},
+		"nil":        []*store.BrokerOffset{nil},
+	}
+	assert.NoError(t, r.ReportBrokerOffsets(offsets))
+
+	assert.Equal(t, []string{
+		"kafka.broker.test_topic.0.available:990|g|#env:test",
+		"kafka.broker.test_topic.0.newest:1000|g|#env:test",
+		"kafka.broker.test_topic.0.oldest:10|g|#env:test",
+	}, statsdLines(buf))
+}
+
+func TestStatsdReporter_ReportBrokerMetadata(t *testing.T) {
+	buf := bytes.NewBuffer([]byte{})
+	r := reporter.NewStatsdReporter(buf, reporter.StatsdPrefix("kafka"))
+
+	metadata := &store.BrokerMetadata{
+		"test": []*store.Metadata{{Leader: 1, Replicas: []int32{1, 2}, Isr: []int32{1}}},
+		"nil":  []*store.Metadata{nil},
+	}
+	assert.NoError(t, r.ReportBrokerMetadata(metadata))
+
+	assert.Equal(t, []string{
+		"kafka.metadata.test.0.isr:1|g",
+		"kafka.metadata.test.0.isr_diff:1|g",
+		"kafka.metadata.test.0.leaders:1|g",
+		"kafka.metadata.test.0.replicas:2|g",
+	}, statsdLines(buf))
+}
+
+func TestStatsdReporter_ReportBrokerStatus(t *testing.T) {
+	buf := bytes.NewBuffer([]byte{})
+	r := reporter.NewStatsdReporter(buf, reporter.StatsdPrefix("kafka"))
+
+	statuses := &store.BrokerStatuses{{ID: 1, Connected: true, Controller: false, Leaders: 3, Replicas: 6}}
+	assert.NoError(t, r.ReportBrokerStatus(statuses))
+
+	assert.Equal(t, []string{
+		"kafka.brokers.1.connected:1|g",
+		"kafka.brokers.1.controller:0|g",
+		"kafka.brokers.1.leaders:3|g",
+		"kafka.brokers.1.replicas:6|g",
+	}, statsdLines(buf))
+}
+
+func TestStatsdReporter_ReportConsumerOffsets(t *testing.T) {
+	buf := bytes.NewBuffer([]byte{})
+	r := reporter.NewStatsdReporter(buf, reporter.StatsdPrefix("kafka"))
+
+	offsets := &store.ConsumerOffsets{
+		"foo": map[string][]*store.ConsumerOffset{
+			"test": {{Offset: 1000, Lag: 100}, nil},
+		},
+	}
+	assert.NoError(t, r.ReportConsumerOffsets(offsets))
+
+	assert.Equal(t, []string{
+		"kafka.consumer.foo.test.0.lag:100|g",
+		"kafka.consumer.foo.test.0.offset:1000|g",
+	}, statsdLines(buf))
+}
+
+func TestStatsdReporter_ReportOffsetRegressions(t *testing.T) {
+	buf := bytes.NewBuffer([]byte{})
+	r := reporter.NewStatsdReporter(buf, reporter.StatsdPrefix("kafka"))
+
+	regressions := &store.OffsetRegressions{
+		{Kind: store.RegressionRewind, Group: "foo", Topic: "test", Partition: 1},
+	}
+	assert.NoError(t, r.ReportOffsetRegressions(regressions))
+
+	assert.Equal(t, []string{"kafka.regression.foo.test.1.rewind:1|c"}, statsdLines(buf))
+}
+
+func TestStatsdReporter_ReportMetrics(t *testing.T) {
+	buf := bytes.NewBuffer([]byte{})
+	r := reporter.NewStatsdReporter(buf, reporter.StatsdPrefix("kafka"))
+
+	snapshot := &metrics.Snapshot{
+		{
+			Name:   "monitor.collect.errors",
+			Type:   metrics.TypeCounter,
+			Tags:   map[string]string{"phase": "broker_offsets"},
+			Values: map[string]float64{"count": 2},
+		},
+	}
+	assert.NoError(t, r.ReportMetrics(snapshot))
+
+	assert.Equal(t, []string{"kafka.kage.monitor_collect_errors.broker_offsets.count:2|g"}, statsdLines(buf))
+}
+
+func TestStatsdReporter_WriteError(t *testing.T) {
+	r := reporter.NewStatsdReporter(errorWriter{}, reporter.StatsdLog(testutil.Logger))
+
+	offsets := &store.BrokerOffsets{
+		"test": []*store.BrokerOffset{{OldestOffset: 0, NewestOffset: 1000}},
+	}
+
+	assert.Error(t, r.ReportBrokerOffsets(offsets))
+}
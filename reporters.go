@@ -1,20 +1,35 @@
 package kage
 
-import "github.com/msales/kage/store"
+import (
+	"sync"
+
+	"github.com/msales/kage/metrics"
+	"github.com/msales/kage/store"
+)
 
 // Reporter represents a offset reporter.
 type Reporter interface {
 	// ReportBrokerOffsets reports a snapshot of the broker offsets.
-	ReportBrokerOffsets(o *store.BrokerOffsets)
+	ReportBrokerOffsets(o *store.BrokerOffsets) error
 
 	// ReportBrokerMetadata reports a snapshot of the broker metadata.
-	ReportBrokerMetadata(o *store.BrokerMetadata)
+	ReportBrokerMetadata(o *store.BrokerMetadata) error
+
+	// ReportBrokerStatus reports the status of the brokers.
+	ReportBrokerStatus(s *store.BrokerStatuses) error
 
 	// ReportConsumerOffsets reports a snapshot of the consumer group offsets.
-	ReportConsumerOffsets(o *store.ConsumerOffsets)
+	ReportConsumerOffsets(o *store.ConsumerOffsets) error
+
+	// ReportOffsetRegressions reports newly detected consumer offset regressions.
+	ReportOffsetRegressions(r *store.OffsetRegressions) error
+
+	// ReportMetrics reports a snapshot of the internal metrics.
+	ReportMetrics(m *metrics.Snapshot) error
 }
 
-// Reporters represents a set of reporters.
+// Reporters represents a set of reporters. Reports are sent to all
+// reporters concurrently.
 type Reporters map[string]Reporter
 
 // Add adds a Reporter to the set.
@@ -24,21 +39,57 @@ func (rs *Reporters) Add(key string, r Reporter) {
 
 // ReportBrokerOffsets reports a snapshot of the broker offsets on all reporters.
 func (rs *Reporters) ReportBrokerOffsets(v *store.BrokerOffsets) {
-	for _, r := range *rs {
+	rs.dispatch(func(r Reporter) {
 		r.ReportBrokerOffsets(v)
-	}
+	})
 }
 
 // ReportBrokerMetadata reports a snapshot of the broker metadata.
 func (rs *Reporters) ReportBrokerMetadata(v *store.BrokerMetadata) {
-	for _, r := range *rs {
+	rs.dispatch(func(r Reporter) {
 		r.ReportBrokerMetadata(v)
-	}
+	})
+}
+
+// ReportBrokerStatus reports the status of the brokers on all reporters.
+func (rs *Reporters) ReportBrokerStatus(v *store.BrokerStatuses) {
+	rs.dispatch(func(r Reporter) {
+		r.ReportBrokerStatus(v)
+	})
 }
 
 // ReportConsumerOffsets reports a snapshot of the consumer group offsets on all reporters.
 func (rs *Reporters) ReportConsumerOffsets(v *store.ConsumerOffsets) {
-	for _, r := range *rs {
+	rs.dispatch(func(r Reporter) {
 		r.ReportConsumerOffsets(v)
+	})
+}
+
+// ReportOffsetRegressions reports newly detected consumer offset regressions on all reporters.
+func (rs *Reporters) ReportOffsetRegressions(v *store.OffsetRegressions) {
+	rs.dispatch(func(r Reporter) {
+		r.ReportOffsetRegressions(v)
+	})
+}
+
+// ReportMetrics reports a snapshot of the internal metrics on all reporters.
+func (rs *Reporters) ReportMetrics(v *metrics.Snapshot) {
+	rs.dispatch(func(r Reporter) {
+		r.ReportMetrics(v)
+	})
+}
+
+// dispatch calls fn concurrently for each reporter, waiting for all to return.
+func (rs *Reporters) dispatch(fn func(r Reporter)) {
+	var wg sync.WaitGroup
+	for _, r := range *rs {
+		wg.Add(1)
+		go func(r Reporter) {
+			defer wg.Done()
+
+			fn(r)
+		}(r)
 	}
+
+	wg.Wait()
 }
@@ -2,8 +2,10 @@ package kage_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/msales/kage"
+	"github.com/msales/kage/metrics"
 	"github.com/msales/kage/store"
 	"github.com/msales/kage/testutil/mocks"
 	"github.com/stretchr/testify/assert"
@@ -26,13 +28,13 @@ func TestReporters_ReportBrokerOffsets(t *testing.T) {
 	m1 := new(mocks.MockReporter)
 	m1.On("ReportBrokerOffsets", mock.AnythingOfType("*store.BrokerOffsets")).Run(func(args mock.Arguments) {
 		assert.Equal(t, offsets, args.Get(0))
-	})
+	}).Return(nil)
 	rs.Add("test1", m1)
 
 	m2 := new(mocks.MockReporter)
 	m2.On("ReportBrokerOffsets", mock.AnythingOfType("*store.BrokerOffsets")).Run(func(args mock.Arguments) {
 		assert.Equal(t, offsets, args.Get(0))
-	})
+	}).Return(nil)
 	rs.Add("test2", m2)
 
 	rs.ReportBrokerOffsets(offsets)
@@ -47,13 +49,13 @@ func TestReporters_ReportConsumerOffsets(t *testing.T) {
 	m1 := new(mocks.MockReporter)
 	m1.On("ReportConsumerOffsets", mock.AnythingOfType("*store.ConsumerOffsets")).Run(func(args mock.Arguments) {
 		assert.Equal(t, offsets, args.Get(0))
-	})
+	}).Return(nil)
 	rs.Add("test1", m1)
 
 	m2 := new(mocks.MockReporter)
 	m2.On("ReportConsumerOffsets", mock.AnythingOfType("*store.ConsumerOffsets")).Run(func(args mock.Arguments) {
 		assert.Equal(t, offsets, args.Get(0))
-	})
+	}).Return(nil)
 	rs.Add("test2", m2)
 
 	rs.ReportConsumerOffsets(offsets)
@@ -68,16 +70,103 @@ func TestReporters_ReportBrokerMetadata(t *testing.T) {
 	m1 := new(mocks.MockReporter)
 	m1.On("ReportBrokerMetadata", mock.AnythingOfType("*store.BrokerMetadata")).Run(func(args mock.Arguments) {
 		assert.Equal(t, metadata, args.Get(0))
-	})
+	}).Return(nil)
 	rs.Add("test1", m1)
 
 	m2 := new(mocks.MockReporter)
 	m2.On("ReportBrokerMetadata", mock.AnythingOfType("*store.BrokerMetadata")).Run(func(args mock.Arguments) {
 		assert.Equal(t, metadata, args.Get(0))
-	})
+	}).Return(nil)
 	rs.Add("test2", m2)
 
 	rs.ReportBrokerMetadata(metadata)
 
 	m1.AssertExpectations(t)
 }
+
+func TestReporters_ReportBrokerStatus(t *testing.T) {
+	rs := kage.Reporters{}
+	statuses := &store.BrokerStatuses{}
+
+	m1 := new(mocks.MockReporter)
+	m1.On("ReportBrokerStatus", statuses).Return(nil)
+	rs.Add("test1", m1)
+
+	m2 := new(mocks.MockReporter)
+	m2.On("ReportBrokerStatus", statuses).Return(nil)
+	rs.Add("test2", m2)
+
+	rs.ReportBrokerStatus(statuses)
+
+	m1.AssertExpectations(t)
+	m2.AssertExpectations(t)
+}
+
+func TestReporters_ReportOffsetRegressions(t *testing.T) {
+	rs := kage.Reporters{}
+	regressions := &store.OffsetRegressions{}
+
+	m1 := new(mocks.MockReporter)
+	m1.On("ReportOffsetRegressions", mock.AnythingOfType("*store.OffsetRegressions")).Run(func(args mock.Arguments) {
+		assert.Equal(t, regressions, args.Get(0))
+	}).Return(nil)
+	rs.Add("test1", m1)
+
+	m2 := new(mocks.MockReporter)
+	m2.On("ReportOffsetRegressions", mock.AnythingOfType("*store.OffsetRegressions")).Run(func(args mock.Arguments) {
+		assert.Equal(t, regressions, args.Get(0))
+	}).Return(nil)
+	rs.Add("test2", m2)
+
+	rs.ReportOffsetRegressions(regressions)
+
+	m1.AssertExpectations(t)
+}
+
+func TestReporters_ReportMetrics(t *testing.T) {
+	rs := kage.Reporters{}
+	snapshot := &metrics.Snapshot{}
+
+	m1 := new(mocks.MockReporter)
+	m1.On("ReportMetrics", mock.AnythingOfType("*metrics.Snapshot")).Run(func(args mock.Arguments) {
+		assert.Equal(t, snapshot, args.Get(0))
+	}).Return(nil)
+	rs.Add("test1", m1)
+
+	m2 := new(mocks.MockReporter)
+	m2.On("ReportMetrics", mock.AnythingOfType("*metrics.Snapshot")).Run(func(args mock.Arguments) {
+		assert.Equal(t, snapshot, args.Get(0))
+	}).Return(nil)
+	rs.Add("test2", m2)
+
+	rs.ReportMetrics(snapshot)
+
+	m1.AssertExpectations(t)
+}
+
+func TestReporters_Concurrent(t *testing.T) {
+	rs := kage.Reporters{}
+	offsets := &store.BrokerOffsets{}
+	called := make(chan struct{})
+
+	m1 := new(mocks.MockReporter)
+	m1.On("ReportBrokerOffsets", offsets).Return(nil).Run(func(args mock.Arguments) {
+		select {
+		case <-called:
+		case <-time.After(time.Second):
+			t.Error("reporters were not called concurrently")
+		}
+	})
+	rs.Add("test1", m1)
+
+	m2 := new(mocks.MockReporter)
+	m2.On("ReportBrokerOffsets", offsets).Return(nil).Run(func(args mock.Arguments) {
+		close(called)
+	})
+	rs.Add("test2", m2)
+
+	rs.ReportBrokerOffsets(offsets)
+
+	m1.AssertExpectations(t)
+	m2.AssertExpectations(t)
+}
@@ -0,0 +1,198 @@
+package kage
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/msales/kage/metrics"
+	"github.com/msales/kage/store"
+)
+
+var (
+	// ErrReportTimeout is returned when a report does not complete within the timeout.
+	ErrReportTimeout = errors.New("kage: report timed out")
+
+	// ErrReporterBusy is returned when a previous report has not yet completed.
+	ErrReporterBusy = errors.New("kage: reporter busy")
+)
+
+// RetryReporterFunc represents a configuration function for RetryReporter.
+type RetryReporterFunc func(r *RetryReporter)
+
+// ReportTimeout configures the time a report may take on a RetryReporter.
+func ReportTimeout(d time.Duration) RetryReporterFunc {
+	return func(r *RetryReporter) {
+		r.timeout = d
+	}
+}
+
+// RetryBuffer configures the number of failed reports kept for retry on a RetryReporter.
+func RetryBuffer(size int) RetryReporterFunc {
+	return func(r *RetryReporter) {
+		r.size = size
+	}
+}
+
+// RetryMetrics configures the internal metrics registry on a RetryReporter.
+func RetryMetrics(name string, m *metrics.Registry) RetryReporterFunc {
+	return func(r *RetryReporter) {
+		r.name = name
+		r.metrics = m
+	}
+}
+
+// RetryReporter bounds the time a Reporter may take, and keeps failed
+// reports in a buffer, replaying them in order once the Reporter recovers.
+type RetryReporter struct {
+	reporter Reporter
+
+	timeout time.Duration
+	size    int
+
+	lock     sync.Mutex
+	pending  []*retryReport
+	inflight chan struct{}
+
+	name    string
+	metrics *metrics.Registry
+}
+
+// NewRetryReporter creates and returns a new RetryReporter.
+func NewRetryReporter(r Reporter, opts ...RetryReporterFunc) *RetryReporter {
+	rr := &RetryReporter{
+		reporter: r,
+	}
+
+	for _, o := range opts {
+		o(rr)
+	}
+
+	return rr
+}
+
+// ReportBrokerOffsets reports a snapshot of the broker offsets.
+func (r *RetryReporter) ReportBrokerOffsets(o *store.BrokerOffsets) error {
+	return r.report(func() error {
+		return r.reporter.ReportBrokerOffsets(o)
+	})
+}
+
+// ReportBrokerMetadata reports a snapshot of the broker metadata.
+func (r *RetryReporter) ReportBrokerMetadata(m *store.BrokerMetadata) error {
+	return r.report(func() error {
+		return r.reporter.ReportBrokerMetadata(m)
+	})
+}
+
+// ReportBrokerStatus reports the status of the brokers.
+func (r *RetryReporter) ReportBrokerStatus(s *store.BrokerStatuses) error {
+	return r.report(func() error {
+		return r.reporter.ReportBrokerStatus(s)
+	})
+}
+
+// ReportConsumerOffsets reports a snapshot of the consumer group offsets.
+func (r *RetryReporter) ReportConsumerOffsets(o *store.ConsumerOffsets) error {
+	return r.report(func() error {
+		return r.reporter.ReportConsumerOffsets(o)
+	})
+}
+
+// ReportOffsetRegressions reports newly detected consumer offset regressions.
+func (r *RetryReporter) ReportOffsetRegressions(o *store.OffsetRegressions) error {
+	return r.report(func() error {
+		return r.reporter.ReportOffsetRegressions(o)
+	})
+}
+
+// ReportMetrics reports a snapshot of the internal metrics.
+func (r *RetryReporter) ReportMetrics(m *metrics.Snapshot) error {
+	return r.report(func() error {
+		return r.reporter.ReportMetrics(m)
+	})
+}
+
+// report sends the buffered reports followed by the given report, stopping
+// at the first failure. Reports that could not be sent are kept for retry,
+// dropping the oldest when the buffer is full.
+func (r *RetryReporter) report(fn func() error) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.pending = append(r.pending, &retryReport{fn: fn})
+
+	var err error
+	for len(r.pending) > 0 {
+		if err = r.call(r.pending[0]); err != nil {
+			break
+		}
+
+		r.pending = r.pending[1:]
+	}
+
+	if len(r.pending) > r.size {
+		dropped := len(r.pending) - r.size
+		r.pending = r.pending[dropped:]
+		r.metrics.Counter("reporter.retry.dropped", "reporter", r.name).Inc(int64(dropped))
+	}
+	r.metrics.Gauge("reporter.retry.buffered", "reporter", r.name).Set(float64(len(r.pending)))
+
+	return err
+}
+
+// retryReport represents a report kept for retry.
+type retryReport struct {
+	fn func() error
+
+	// result receives the outcome of a call that timed out.
+	result chan error
+}
+
+// call calls the report, waiting at most for the timeout. A call is not
+// made while a previous call that timed out is still running, and a report
+// that timed out is not called again once its call succeeded.
+func (r *RetryReporter) call(report *retryReport) error {
+	if r.inflight != nil {
+		select {
+		case <-r.inflight:
+			r.inflight = nil
+		default:
+			return ErrReporterBusy
+		}
+	}
+
+	if report.result != nil {
+		err := <-report.result
+		report.result = nil
+		if err == nil {
+			return nil
+		}
+	}
+
+	done := make(chan struct{})
+	result := make(chan error, 1)
+	r.inflight = done
+	go func() {
+		defer close(done)
+
+		result <- report.fn()
+	}()
+
+	if r.timeout <= 0 {
+		return <-result
+	}
+
+	timer := time.NewTimer(r.timeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-result:
+		return err
+
+	case <-timer.C:
+		report.result = result
+		r.metrics.Counter("reporter.timeouts", "reporter", r.name).Inc(1)
+		return ErrReportTimeout
+	}
+}
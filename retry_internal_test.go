@@ -0,0 +1,35 @@
+package kage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/msales/kage/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReportTimeout(t *testing.T) {
+	r := &RetryReporter{}
+
+	ReportTimeout(time.Second)(r)
+
+	assert.Equal(t, time.Second, r.timeout)
+}
+
+func TestRetryBuffer(t *testing.T) {
+	r := &RetryReporter{}
+
+	RetryBuffer(10)(r)
+
+	assert.Equal(t, 10, r.size)
+}
+
+func TestRetryMetrics(t *testing.T) {
+	m := metrics.NewRegistry()
+	r := &RetryReporter{}
+
+	RetryMetrics("test", m)(r)
+
+	assert.Equal(t, "test", r.name)
+	assert.Equal(t, m, r.metrics)
+}
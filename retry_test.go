@@ -0,0 +1,150 @@
+package kage_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/msales/kage"
+	"github.com/msales/kage/metrics"
+	"github.com/msales/kage/store"
+	"github.com/msales/kage/testutil/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRetryReporter_Report(t *testing.T) {
+	bo := &store.BrokerOffsets{}
+	bm := &store.BrokerMetadata{}
+	bs := &store.BrokerStatuses{}
+	co := &store.ConsumerOffsets{}
+	or := &store.OffsetRegressions{}
+	m := &metrics.Snapshot{}
+
+	reporter := new(mocks.MockReporter)
+	reporter.On("ReportBrokerOffsets", bo).Return(nil)
+	reporter.On("ReportBrokerMetadata", bm).Return(nil)
+	reporter.On("ReportBrokerStatus", bs).Return(nil)
+	reporter.On("ReportConsumerOffsets", co).Return(nil)
+	reporter.On("ReportOffsetRegressions", or).Return(nil)
+	reporter.On("ReportMetrics", m).Return(nil)
+
+	r := kage.NewRetryReporter(reporter)
+
+	assert.NoError(t, r.ReportBrokerOffsets(bo))
+	assert.NoError(t, r.ReportBrokerMetadata(bm))
+	assert.NoError(t, r.ReportBrokerStatus(bs))
+	assert.NoError(t, r.ReportConsumerOffsets(co))
+	assert.NoError(t, r.ReportOffsetRegressions(or))
+	assert.NoError(t, r.ReportMetrics(m))
+	reporter.AssertExpectations(t)
+}
+
+func TestRetryReporter_ReplaysFailedReports(t *testing.T) {
+	bo1 := &store.BrokerOffsets{"first": nil}
+	bo2 := &store.BrokerOffsets{"second": nil}
+	calls := []*store.BrokerOffsets{}
+
+	reporter := new(mocks.MockReporter)
+	reporter.On("ReportBrokerOffsets", bo1).Return(errors.New("test error")).Once()
+	reporter.On("ReportBrokerOffsets", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		calls = append(calls, args.Get(0).(*store.BrokerOffsets))
+	})
+
+	r := kage.NewRetryReporter(reporter, kage.RetryBuffer(10))
+
+	assert.Error(t, r.ReportBrokerOffsets(bo1))
+	assert.NoError(t, r.ReportBrokerOffsets(bo2))
+	assert.Equal(t, []*store.BrokerOffsets{bo1, bo2}, calls)
+}
+
+func TestRetryReporter_DropsOldestReports(t *testing.T) {
+	bo1 := &store.BrokerOffsets{"first": nil}
+	bo2 := &store.BrokerOffsets{"second": nil}
+	bo3 := &store.BrokerOffsets{"third": nil}
+	calls := []*store.BrokerOffsets{}
+	fail := true
+
+	reporter := new(mocks.MockReporter)
+	reporter.On("ReportBrokerOffsets", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		calls = append(calls, args.Get(0).(*store.BrokerOffsets))
+	})
+
+	failing := &failingReporter{MockReporter: reporter, fail: &fail}
+	registry := metrics.NewRegistry()
+	r := kage.NewRetryReporter(failing, kage.RetryBuffer(2), kage.RetryMetrics("test", registry))
+
+	assert.Error(t, r.ReportBrokerOffsets(bo1))
+	assert.Error(t, r.ReportBrokerOffsets(bo2))
+	assert.Error(t, r.ReportBrokerOffsets(bo3))
+
+	fail = false
+	calls = nil
+
+	assert.NoError(t, r.ReportBrokerOffsets(&store.BrokerOffsets{}))
+	assert.Equal(t, []*store.BrokerOffsets{bo2, bo3, {}}, calls)
+
+	snapshot := registry.Snapshot()
+	assert.Equal(t, "reporter.retry.buffered", snapshot[0].Name)
+	assert.Equal(t, float64(0), snapshot[0].Values["value"])
+	assert.Equal(t, "reporter.retry.dropped", snapshot[1].Name)
+	assert.Equal(t, float64(1), snapshot[1].Values["count"])
+}
+
+func TestRetryReporter_Timeout(t *testing.T) {
+	bo := &store.BrokerOffsets{}
+	release := make(chan struct{})
+
+	reporter := new(mocks.MockReporter)
+	reporter.On("ReportBrokerOffsets", bo).Return(nil).Run(func(args mock.Arguments) {
+		<-release
+	})
+
+	r := kage.NewRetryReporter(reporter, kage.ReportTimeout(10*time.Millisecond), kage.RetryBuffer(10))
+
+	assert.Equal(t, kage.ErrReportTimeout, r.ReportBrokerOffsets(bo))
+	assert.Equal(t, kage.ErrReporterBusy, r.ReportBrokerOffsets(bo))
+
+	close(release)
+	time.Sleep(10 * time.Millisecond)
+
+	// The report that timed out succeeded, so it is not sent again
+	assert.NoError(t, r.ReportBrokerOffsets(bo))
+	reporter.AssertNumberOfCalls(t, "ReportBrokerOffsets", 3)
+}
+
+func TestRetryReporter_TimeoutFailure(t *testing.T) {
+	bo := &store.BrokerOffsets{}
+	release := make(chan struct{})
+
+	reporter := new(mocks.MockReporter)
+	reporter.On("ReportBrokerOffsets", bo).Return(errors.New("test error")).Run(func(args mock.Arguments) {
+		<-release
+	}).Once()
+	reporter.On("ReportBrokerOffsets", bo).Return(nil)
+
+	r := kage.NewRetryReporter(reporter, kage.ReportTimeout(10*time.Millisecond), kage.RetryBuffer(10))
+
+	assert.Equal(t, kage.ErrReportTimeout, r.ReportBrokerOffsets(bo))
+
+	close(release)
+	time.Sleep(10 * time.Millisecond)
+
+	// The report that timed out failed, so it is sent again
+	assert.NoError(t, r.ReportBrokerOffsets(bo))
+	reporter.AssertNumberOfCalls(t, "ReportBrokerOffsets", 3)
+}
+
+type failingReporter struct {
+	*mocks.MockReporter
+
+	fail *bool
+}
+
+func (r *failingReporter) ReportBrokerOffsets(v *store.BrokerOffsets) error {
+	if *r.fail {
+		return errors.New("test error")
+	}
+
+	return r.MockReporter.ReportBrokerOffsets(v)
+}
@@ -0,0 +1,68 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/msales/kage/utils"
+)
+
+type activeAlert struct {
+	Name        string            `json:"name"`
+	State       string            `json:"state"`
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+	Value       float64           `json:"value"`
+	ActiveAt    int64             `json:"active_at"`
+	FiredAt     int64             `json:"fired_at,omitempty"`
+}
+
+type activeAlertList []activeAlert
+
+func (l activeAlertList) Header() []string {
+	return []string{"name", "state", "labels", "value", "active_at", "fired_at"}
+}
+
+func (l activeAlertList) Rows() [][]string {
+	rows := [][]string{}
+	for _, a := range l {
+		labels := []string{}
+		for key, value := range a.Labels {
+			labels = append(labels, key+"="+value)
+		}
+		sort.Strings(labels)
+
+		rows = append(rows, []string{
+			a.Name,
+			a.State,
+			strings.Join(labels, ","),
+			fmt.Sprint(a.Value),
+			fmt.Sprint(a.ActiveAt),
+			fmt.Sprint(a.FiredAt),
+		})
+	}
+
+	return rows
+}
+
+// AlertsHandler handles requests for the pending and firing alerts.
+func (s *Server) AlertsHandler(w http.ResponseWriter, r *http.Request) {
+	alerts := activeAlertList{}
+	if s.Alerts != nil {
+		for _, a := range s.Alerts.Alerts() {
+			alerts = append(alerts, activeAlert{
+				Name:        a.Name,
+				State:       string(a.State),
+				Labels:      a.Labels,
+				Annotations: a.Annotations,
+				Value:       a.Value,
+				ActiveAt:    utils.Millis(a.ActiveAt),
+				FiredAt:     utils.Millis(a.FiredAt),
+			})
+		}
+	}
+
+	s.write(w, r, alerts)
+}
@@ -0,0 +1,55 @@
+package server_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/msales/kage"
+	"github.com/msales/kage/alert"
+	"github.com/msales/kage/server"
+	"github.com/msales/kage/store"
+	"github.com/msales/kage/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlertsHandler(t *testing.T) {
+	req, err := http.NewRequest("GET", "/alerts", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+
+	rules := []*alert.Rule{{Name: "HighLag", Metric: alert.MetricLag, Op: ">", Threshold: 1000}}
+	engine := alert.NewEngine(rules, alert.Log(testutil.Logger))
+	engine.Evaluate(&alert.Snapshot{
+		ConsumerOffsets: store.ConsumerOffsets{"foo": {"test": {{Offset: 100, Lag: 2000}}}},
+		Time:            time.Unix(1500000000, 0),
+	})
+
+	app := &kage.Application{Alerts: engine}
+
+	srv := server.New(app)
+	srv.ServeHTTP(rr, req)
+
+	want := "[{\"name\":\"HighLag\",\"state\":\"firing\",\"labels\":{\"group\":\"foo\",\"topic\":\"test\"},\"annotations\":null,\"value\":2000,\"active_at\":1500000000000,\"fired_at\":1500000000000}]"
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, want, rr.Body.String())
+}
+
+func TestAlertsHandler_NoRules(t *testing.T) {
+	req, err := http.NewRequest("GET", "/v1/alerts", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+
+	srv := server.New(&kage.Application{})
+	srv.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "[]", rr.Body.String())
+}
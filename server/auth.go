@@ -0,0 +1,53 @@
+package server
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// unauthenticatedPaths are the paths that can be requested without authentication.
+var unauthenticatedPaths = []string{"/health", apiVersionPrefix + "/health"}
+
+// authEnabled determines if authentication is configured on the Server.
+func (s *Server) authEnabled() bool {
+	return s.username != "" || s.token != ""
+}
+
+// authenticate determines if the request is authenticated.
+func (s *Server) authenticate(r *http.Request) bool {
+	if !s.authEnabled() || containsString(unauthenticatedPaths, r.URL.Path) {
+		return true
+	}
+
+	if s.username != "" {
+		if user, pass, ok := r.BasicAuth(); ok {
+			return secureCompare(user, s.username) && secureCompare(pass, s.password)
+		}
+	}
+
+	if s.token != "" {
+		auth := r.Header.Get("Authorization")
+		if strings.HasPrefix(auth, "Bearer ") {
+			return secureCompare(strings.TrimPrefix(auth, "Bearer "), s.token)
+		}
+	}
+
+	return false
+}
+
+// unauthorized writes an authentication challenge to the response.
+func (s *Server) unauthorized(w http.ResponseWriter) {
+	if s.username != "" {
+		w.Header().Set("WWW-Authenticate", `Basic realm="kage"`)
+	} else {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="kage"`)
+	}
+
+	w.WriteHeader(http.StatusUnauthorized)
+}
+
+// secureCompare compares two strings in constant time.
+func secureCompare(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
@@ -0,0 +1,106 @@
+package server_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/msales/kage"
+	"github.com/msales/kage/kafka"
+	"github.com/msales/kage/server"
+	"github.com/msales/kage/testutil/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuth_BasicAuth(t *testing.T) {
+	monitor := new(mocks.MockMonitor)
+	monitor.On("Brokers").Return([]kafka.Broker{{ID: 0, Connected: true}})
+
+	srv := server.New(&kage.Application{Monitor: monitor}, server.BasicAuth("user", "pass"))
+
+	tests := []struct {
+		user string
+		pass string
+		code int
+	}{
+		{user: "user", pass: "pass", code: http.StatusOK},
+		{user: "user", pass: "wrong", code: http.StatusUnauthorized},
+		{user: "", pass: "", code: http.StatusUnauthorized},
+	}
+
+	for _, test := range tests {
+		req, err := http.NewRequest("GET", "/brokers", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if test.user != "" {
+			req.SetBasicAuth(test.user, test.pass)
+		}
+
+		rr := httptest.NewRecorder()
+		srv.ServeHTTP(rr, req)
+
+		assert.Equal(t, test.code, rr.Code)
+	}
+}
+
+func TestAuth_BearerToken(t *testing.T) {
+	monitor := new(mocks.MockMonitor)
+	monitor.On("Brokers").Return([]kafka.Broker{{ID: 0, Connected: true}})
+
+	srv := server.New(&kage.Application{Monitor: monitor}, server.BearerToken("secret"))
+
+	tests := []struct {
+		auth string
+		code int
+	}{
+		{auth: "Bearer secret", code: http.StatusOK},
+		{auth: "Bearer wrong", code: http.StatusUnauthorized},
+		{auth: "", code: http.StatusUnauthorized},
+	}
+
+	for _, test := range tests {
+		req, err := http.NewRequest("GET", "/brokers", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Authorization", test.auth)
+
+		rr := httptest.NewRecorder()
+		srv.ServeHTTP(rr, req)
+
+		assert.Equal(t, test.code, rr.Code)
+	}
+}
+
+func TestAuth_Unauthorized(t *testing.T) {
+	req, err := http.NewRequest("GET", "/brokers", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+
+	srv := server.New(&kage.Application{}, server.BasicAuth("user", "pass"))
+	srv.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.Equal(t, `Basic realm="kage"`, rr.Header().Get("WWW-Authenticate"))
+}
+
+func TestAuth_HealthUnauthenticated(t *testing.T) {
+	req, err := http.NewRequest("GET", "/health", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+
+	monitor := new(mocks.MockMonitor)
+	monitor.On("IsHealthy").Return(true)
+
+	srv := server.New(&kage.Application{Monitor: monitor}, server.BearerToken("secret"))
+	srv.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
@@ -1,7 +1,9 @@
 package server
 
 import (
+	"fmt"
 	"net/http"
+	"sort"
 
 	"github.com/go-zoo/bone"
 	"github.com/msales/kage/store"
@@ -20,20 +22,56 @@ type consumerPartition struct {
 	Lag       int64 `json:"lag"`
 }
 
+type consumerGroupList []consumerGroup
+
+func (l consumerGroupList) Header() []string {
+	return []string{"group", "topic", "partition", "offset", "lag"}
+}
+
+func (l consumerGroupList) Rows() [][]string {
+	rows := [][]string{}
+	for _, g := range l {
+		for _, p := range g.Partitions {
+			rows = append(rows, []string{g.Group, g.Topic, fmt.Sprint(p.Partition), fmt.Sprint(p.Offset), fmt.Sprint(p.Lag)})
+		}
+	}
+
+	return rows
+}
+
+// consumerGroupSorts are the sort fields of consumer group lists.
+var consumerGroupSorts = []string{"group", "topic", "lag"}
+
 // ConsumerGroupsHandler handles requests for consumer groups offsets.
 func (s *Server) ConsumerGroupsHandler(w http.ResponseWriter, r *http.Request) {
+	q, err := parseListQuery(r, consumerGroupSorts...)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	offsets := s.Store.ConsumerOffsets()
 
 	groups := []consumerGroup{}
 	for group, topics := range offsets {
+		if !q.MatchGroup(group) {
+			continue
+		}
+
 		groups = append(groups, createConsumerGroup(group, topics)...)
 	}
 
-	s.writeJSON(w, groups)
+	s.write(w, r, queryConsumerGroups(q, groups))
 }
 
 // ConsumerGroupHandler handles requests for a consumer group offsets.
 func (s *Server) ConsumerGroupHandler(w http.ResponseWriter, r *http.Request) {
+	q, err := parseListQuery(r, consumerGroupSorts...)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	offsets := s.Store.ConsumerOffsets()
 
 	group := bone.GetValue(r, "group")
@@ -45,7 +83,42 @@ func (s *Server) ConsumerGroupHandler(w http.ResponseWriter, r *http.Request) {
 
 	groups := createConsumerGroup(group, topics)
 
-	s.writeJSON(w, groups)
+	s.write(w, r, queryConsumerGroups(q, groups))
+}
+
+// queryConsumerGroups filters, sorts and paginates the consumer groups.
+func queryConsumerGroups(q *listQuery, groups []consumerGroup) consumerGroupList {
+	filtered := consumerGroupList{}
+	for _, g := range groups {
+		if !q.MatchGroup(g.Group) || !q.MatchTopic(g.Topic) || g.TotalLag < q.MinLag {
+			continue
+		}
+
+		filtered = append(filtered, g)
+	}
+
+	sort.SliceStable(filtered, func(i, j int) bool {
+		a, b := filtered[i], filtered[j]
+
+		cmp := 0
+		switch q.Sort {
+		case "topic":
+			cmp = compareStrings(a.Topic, b.Topic)
+		case "lag":
+			cmp = compareInts(a.TotalLag, b.TotalLag)
+		}
+		if cmp == 0 {
+			cmp = compareStrings(a.Group, b.Group)
+		}
+		if cmp == 0 {
+			cmp = compareStrings(a.Topic, b.Topic)
+		}
+
+		return q.Less(cmp)
+	})
+
+	start, end := q.Page(len(filtered))
+	return filtered[start:end]
 }
 
 func createConsumerGroup(group string, topics map[string][]*store.ConsumerOffset) []consumerGroup {
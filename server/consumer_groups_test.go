@@ -90,3 +90,95 @@ func TestConsumerGroupHandler_NotFound(t *testing.T) {
 
 	assert.Equal(t, http.StatusNotFound, rr.Code)
 }
+
+func TestConsumerGroupsHandler_Query(t *testing.T) {
+	req, err := http.NewRequest("GET", "/consumers?group=b*&min_lag=10&sort=lag&order=desc&limit=2", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+
+	co := store.ConsumerOffsets{
+		"bar": map[string][]*store.ConsumerOffset{
+			"test":  {{Offset: 0, Lag: 100, Timestamp: 0}},
+			"other": {{Offset: 0, Lag: 5, Timestamp: 0}},
+		},
+		"baz": map[string][]*store.ConsumerOffset{
+			"test":  {{Offset: 0, Lag: 50, Timestamp: 0}},
+			"other": {{Offset: 0, Lag: 500, Timestamp: 0}},
+		},
+		"foo": map[string][]*store.ConsumerOffset{
+			"test": {{Offset: 0, Lag: 1000, Timestamp: 0}},
+		},
+	}
+
+	store := new(mocks.MockStore)
+	store.On("ConsumerOffsets").Return(co)
+
+	app := &kage.Application{Store: store}
+
+	srv := server.New(app)
+	srv.ServeHTTP(rr, req)
+
+	want := "[{\"group\":\"baz\",\"topic\":\"other\",\"total_lag\":500,\"partitions\":[{\"partition\":0,\"offset\":0,\"lag\":500}]}," +
+		"{\"group\":\"bar\",\"topic\":\"test\",\"total_lag\":100,\"partitions\":[{\"partition\":0,\"offset\":0,\"lag\":100}]}]"
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, want, rr.Body.String())
+}
+
+func TestConsumerGroupsHandler_DefaultSort(t *testing.T) {
+	req, err := http.NewRequest("GET", "/consumers?offset=1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+
+	co := store.ConsumerOffsets{
+		"foo": map[string][]*store.ConsumerOffset{
+			"test": {{Offset: 0, Lag: 1000, Timestamp: 0}},
+		},
+		"bar": map[string][]*store.ConsumerOffset{
+			"test":  {{Offset: 0, Lag: 100, Timestamp: 0}},
+			"other": {{Offset: 0, Lag: 5, Timestamp: 0}},
+		},
+	}
+
+	store := new(mocks.MockStore)
+	store.On("ConsumerOffsets").Return(co)
+
+	app := &kage.Application{Store: store}
+
+	srv := server.New(app)
+	srv.ServeHTTP(rr, req)
+
+	want := "[{\"group\":\"bar\",\"topic\":\"test\",\"total_lag\":100,\"partitions\":[{\"partition\":0,\"offset\":0,\"lag\":100}]}," +
+		"{\"group\":\"foo\",\"topic\":\"test\",\"total_lag\":1000,\"partitions\":[{\"partition\":0,\"offset\":0,\"lag\":1000}]}]"
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, want, rr.Body.String())
+}
+
+func TestConsumerGroupsHandler_BadQuery(t *testing.T) {
+	tests := []string{
+		"/consumers?sort=unknown",
+		"/consumers?order=sideways",
+		"/consumers?min_lag=abc",
+		"/consumers?limit=-1",
+		"/consumers?offset=abc",
+	}
+
+	for _, url := range tests {
+		req, err := http.NewRequest("GET", url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rr := httptest.NewRecorder()
+
+		srv := server.New(&kage.Application{})
+		srv.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, url)
+	}
+}
@@ -0,0 +1,60 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+)
+
+type clusterEvent struct {
+	ID        int64   `json:"id"`
+	Type      string  `json:"type"`
+	Topic     string  `json:"topic,omitempty"`
+	Partition int32   `json:"partition"`
+	Group     string  `json:"group,omitempty"`
+	Old       []int32 `json:"old"`
+	New       []int32 `json:"new"`
+	Timestamp int64   `json:"timestamp"`
+}
+
+type clusterEventList []clusterEvent
+
+func (l clusterEventList) Header() []string {
+	return []string{"id", "type", "topic", "partition", "group", "old", "new", "timestamp"}
+}
+
+func (l clusterEventList) Rows() [][]string {
+	rows := [][]string{}
+	for _, e := range l {
+		rows = append(rows, []string{
+			fmt.Sprint(e.ID),
+			e.Type,
+			e.Topic,
+			fmt.Sprint(e.Partition),
+			e.Group,
+			formatInts(e.Old),
+			formatInts(e.New),
+			fmt.Sprint(e.Timestamp),
+		})
+	}
+
+	return rows
+}
+
+// EventsHandler handles requests for cluster events.
+func (s *Server) EventsHandler(w http.ResponseWriter, r *http.Request) {
+	events := clusterEventList{}
+	for _, event := range s.Store.ClusterEvents() {
+		events = append(events, clusterEvent{
+			ID:        event.ID,
+			Type:      event.Type,
+			Topic:     event.Topic,
+			Partition: event.Partition,
+			Group:     event.Group,
+			Old:       event.Old,
+			New:       event.New,
+			Timestamp: event.Timestamp,
+		})
+	}
+
+	s.write(w, r, events)
+}
@@ -0,0 +1,38 @@
+package server_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/msales/kage"
+	"github.com/msales/kage/server"
+	"github.com/msales/kage/store"
+	"github.com/msales/kage/testutil/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventsHandler(t *testing.T) {
+	req, err := http.NewRequest("GET", "/events", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+
+	ce := store.ClusterEvents{
+		{ID: 1, Type: store.EventLeaderChanged, Topic: "test", Partition: 0, Old: []int32{1}, New: []int32{2}, Timestamp: 0},
+	}
+
+	store := new(mocks.MockStore)
+	store.On("ClusterEvents").Return(ce)
+
+	app := &kage.Application{Store: store}
+
+	srv := server.New(app)
+	srv.ServeHTTP(rr, req)
+
+	want := "[{\"id\":1,\"type\":\"leader_changed\",\"topic\":\"test\",\"partition\":0,\"old\":[1],\"new\":[2],\"timestamp\":0}]"
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, want, rr.Body.String())
+}
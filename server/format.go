@@ -0,0 +1,131 @@
+package server
+
+import (
+	"encoding/csv"
+	"fmt"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// Response formats.
+const (
+	formatJSON  = "json"
+	formatCSV   = "csv"
+	formatTable = "table"
+)
+
+// formatTypes maps the media types to their response format.
+var formatTypes = map[string]string{
+	"application/json": formatJSON,
+	"text/csv":         formatCSV,
+	"text/plain":       formatTable,
+}
+
+// tabular represents a response that can be written as a table.
+type tabular interface {
+	// Header returns the column names of the table.
+	Header() []string
+
+	// Rows returns the rows of the table.
+	Rows() [][]string
+}
+
+// negotiateFormat determines the response format from the "format" query
+// parameter or the Accept header of the request.
+func negotiateFormat(r *http.Request) (string, error) {
+	if f := r.URL.Query().Get("format"); f != "" {
+		switch f {
+		case formatJSON, formatCSV, formatTable:
+			return f, nil
+		default:
+			return "", fmt.Errorf("invalid format \"%s\"", f)
+		}
+	}
+
+	type mediaRange struct {
+		typ string
+		q   float64
+	}
+
+	ranges := []mediaRange{}
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		typ, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+
+		ranges = append(ranges, mediaRange{typ: typ, q: q})
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].q > ranges[j].q
+	})
+
+	for _, mr := range ranges {
+		if f, ok := formatTypes[mr.typ]; ok && mr.q > 0 {
+			return f, nil
+		}
+	}
+
+	return formatJSON, nil
+}
+
+// write writes the response in the format negotiated with the request.
+func (s *Server) write(w http.ResponseWriter, r *http.Request, v tabular) {
+	format, err := negotiateFormat(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	switch format {
+	case formatCSV:
+		s.writeCSV(w, v)
+
+	case formatTable:
+		s.writeTable(w, v)
+
+	default:
+		s.writeJSON(w, v)
+	}
+}
+
+func (s *Server) writeCSV(w http.ResponseWriter, v tabular) {
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+
+	cw := csv.NewWriter(w)
+	cw.Write(v.Header())
+	cw.WriteAll(v.Rows())
+}
+
+func (s *Server) writeTable(w http.ResponseWriter, v tabular) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(v.Header(), "\t"))
+	for _, row := range v.Rows() {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	tw.Flush()
+}
+
+// formatInts formats a list of integers as a comma separated string.
+func formatInts(v []int32) string {
+	s := make([]string, len(v))
+	for i, n := range v {
+		s[i] = strconv.FormatInt(int64(n), 10)
+	}
+
+	return strings.Join(s, ",")
+}
@@ -0,0 +1,113 @@
+package server_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/msales/kage"
+	"github.com/msales/kage/server"
+	"github.com/msales/kage/store"
+	"github.com/msales/kage/testutil/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormat_CSV(t *testing.T) {
+	req, err := http.NewRequest("GET", "/consumers?format=csv", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+
+	co := store.ConsumerOffsets{
+		"test": map[string][]*store.ConsumerOffset{
+			"test": {{Offset: 0, Lag: 100, Timestamp: 0}, {Offset: 10, Lag: 90, Timestamp: 0}},
+		},
+	}
+
+	store := new(mocks.MockStore)
+	store.On("ConsumerOffsets").Return(co)
+
+	app := &kage.Application{Store: store}
+
+	srv := server.New(app)
+	srv.ServeHTTP(rr, req)
+
+	want := "group,topic,partition,offset,lag\ntest,test,0,0,100\ntest,test,1,10,90\n"
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", rr.Header().Get("Content-Type"))
+	assert.Equal(t, want, rr.Body.String())
+}
+
+func TestFormat_Table(t *testing.T) {
+	req, err := http.NewRequest("GET", "/metadata", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Accept", "application/json;q=0.5, text/plain")
+
+	rr := httptest.NewRecorder()
+
+	bm := store.BrokerMetadata{
+		"test": []*store.Metadata{{Leader: 1, Replicas: []int32{1, 2}, Isr: []int32{1, 2}, Timestamp: 0}},
+	}
+
+	store := new(mocks.MockStore)
+	store.On("BrokerMetadata").Return(bm)
+
+	app := &kage.Application{Store: store}
+
+	srv := server.New(app)
+	srv.ServeHTTP(rr, req)
+
+	want := "topic  partition  leader  replicas  isr\ntest   0          1       1,2       1,2\n"
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "text/plain; charset=utf-8", rr.Header().Get("Content-Type"))
+	assert.Equal(t, want, rr.Body.String())
+}
+
+func TestFormat_DefaultJSON(t *testing.T) {
+	req, err := http.NewRequest("GET", "/metadata", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Accept", "text/html, */*")
+
+	rr := httptest.NewRecorder()
+
+	bm := store.BrokerMetadata{}
+
+	store := new(mocks.MockStore)
+	store.On("BrokerMetadata").Return(bm)
+
+	app := &kage.Application{Store: store}
+
+	srv := server.New(app)
+	srv.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+	assert.Equal(t, "[]", rr.Body.String())
+}
+
+func TestFormat_Invalid(t *testing.T) {
+	req, err := http.NewRequest("GET", "/metadata?format=xml", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+
+	bm := store.BrokerMetadata{}
+
+	store := new(mocks.MockStore)
+	store.On("BrokerMetadata").Return(bm)
+
+	app := &kage.Application{Store: store}
+
+	srv := server.New(app)
+	srv.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
@@ -1,7 +1,9 @@
 package server
 
 import (
+	"fmt"
 	"net/http"
+	"sort"
 )
 
 type topicMetadata struct {
@@ -16,12 +18,39 @@ type partitionMetadata struct {
 	Isr       []int32 `json:"isr"`
 }
 
+type topicMetadataList []topicMetadata
+
+func (l topicMetadataList) Header() []string {
+	return []string{"topic", "partition", "leader", "replicas", "isr"}
+}
+
+func (l topicMetadataList) Rows() [][]string {
+	rows := [][]string{}
+	for _, t := range l {
+		for _, p := range t.Partitions {
+			rows = append(rows, []string{t.Topic, fmt.Sprint(p.Partition), fmt.Sprint(p.Leader), formatInts(p.Replicas), formatInts(p.Isr)})
+		}
+	}
+
+	return rows
+}
+
 // MetadataHandler handles requests for topic metadata.
 func (s *Server) MetadataHandler(w http.ResponseWriter, r *http.Request) {
+	q, err := parseListQuery(r, "topic", "partitions")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	metadata := s.Store.BrokerMetadata()
 
-	topics := []topicMetadata{}
+	topics := topicMetadataList{}
 	for topic, partitions := range metadata {
+		if !q.MatchTopic(topic) {
+			continue
+		}
+
 		bt := topicMetadata{
 			Topic:      topic,
 			Partitions: make([]partitionMetadata, len(partitions)),
@@ -44,5 +73,18 @@ func (s *Server) MetadataHandler(w http.ResponseWriter, r *http.Request) {
 		topics = append(topics, bt)
 	}
 
-	s.writeJSON(w, topics)
+	sort.SliceStable(topics, func(i, j int) bool {
+		cmp := 0
+		if q.Sort == "partitions" {
+			cmp = compareInts(int64(len(topics[i].Partitions)), int64(len(topics[j].Partitions)))
+		}
+		if cmp == 0 {
+			cmp = compareStrings(topics[i].Topic, topics[j].Topic)
+		}
+
+		return q.Less(cmp)
+	})
+
+	start, end := q.Page(len(topics))
+	s.write(w, r, topics[start:end])
 }
@@ -36,3 +36,30 @@ func TestMetadataHandler(t *testing.T) {
 	assert.Equal(t, http.StatusOK, rr.Code)
 	assert.Equal(t, want, rr.Body.String())
 }
+
+func TestMetadataHandler_Query(t *testing.T) {
+	req, err := http.NewRequest("GET", "/metadata?limit=1&offset=1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+
+	bo := store.BrokerMetadata{
+		"c": []*store.Metadata{{Leader: 1, Replicas: []int32{1}, Isr: []int32{1}, Timestamp: 0}},
+		"b": []*store.Metadata{{Leader: 1, Replicas: []int32{1}, Isr: []int32{1}, Timestamp: 0}},
+		"a": []*store.Metadata{{Leader: 1, Replicas: []int32{1}, Isr: []int32{1}, Timestamp: 0}},
+	}
+
+	store := new(mocks.MockStore)
+	store.On("BrokerMetadata").Return(bo)
+
+	app := &kage.Application{Store: store}
+
+	srv := server.New(app)
+	srv.ServeHTTP(rr, req)
+
+	want := "[{\"topic\":\"b\",\"partitions\":[{\"partition\":0,\"leader\":1,\"replicas\":[1],\"isr\":[1]}]}]"
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, want, rr.Body.String())
+}
@@ -0,0 +1,65 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+type internalMetric struct {
+	Name   string             `json:"name"`
+	Type   string             `json:"type"`
+	Tags   map[string]string  `json:"tags"`
+	Values map[string]float64 `json:"values"`
+}
+
+type internalMetricList []internalMetric
+
+func (l internalMetricList) Header() []string {
+	return []string{"name", "type", "tags", "value_name", "value"}
+}
+
+func (l internalMetricList) Rows() [][]string {
+	rows := [][]string{}
+	for _, m := range l {
+		tags := []string{}
+		for key, value := range m.Tags {
+			tags = append(tags, key+"="+value)
+		}
+		sort.Strings(tags)
+
+		names := []string{}
+		for name := range m.Values {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			rows = append(rows, []string{
+				m.Name,
+				m.Type,
+				strings.Join(tags, ","),
+				name,
+				fmt.Sprint(m.Values[name]),
+			})
+		}
+	}
+
+	return rows
+}
+
+// MetricsHandler handles requests for the internal metrics of kage.
+func (s *Server) MetricsHandler(w http.ResponseWriter, r *http.Request) {
+	metrics := internalMetricList{}
+	for _, m := range s.Metrics.Snapshot() {
+		metrics = append(metrics, internalMetric{
+			Name:   m.Name,
+			Type:   m.Type,
+			Tags:   m.Tags,
+			Values: m.Values,
+		})
+	}
+
+	s.write(w, r, metrics)
+}
@@ -0,0 +1,54 @@
+package server_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/msales/kage"
+	"github.com/msales/kage/metrics"
+	"github.com/msales/kage/server"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricsHandler(t *testing.T) {
+	req, err := http.NewRequest("GET", "/v1/metrics", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+
+	registry := metrics.NewRegistry()
+	registry.Counter("store.objects.unknown").Inc(2)
+
+	app := &kage.Application{Metrics: registry}
+
+	srv := server.New(app)
+	srv.ServeHTTP(rr, req)
+
+	want := "[{\"name\":\"store.objects.unknown\",\"type\":\"counter\",\"tags\":{},\"values\":{\"count\":2}}]"
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, want, rr.Body.String())
+}
+
+func TestMetricsHandlerCSV(t *testing.T) {
+	req, err := http.NewRequest("GET", "/v1/metrics?format=csv", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+
+	registry := metrics.NewRegistry()
+	registry.Gauge("store.channel.depth", "store", "memory").Set(5)
+
+	app := &kage.Application{Metrics: registry}
+
+	srv := server.New(app)
+	srv.ServeHTTP(rr, req)
+
+	want := "name,type,tags,value_name,value\nstore.channel.depth,gauge,store=memory,value,5\n"
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, want, rr.Body.String())
+}
@@ -0,0 +1,412 @@
+package server
+
+import (
+	"net/http"
+)
+
+// OpenAPIHandler handles requests for the OpenAPI description of the API.
+func (s *Server) OpenAPIHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(openAPIDocument))
+}
+
+// openAPIDocument is the OpenAPI description of the v1 API.
+const openAPIDocument = `{
+  "openapi": "3.0.0",
+  "info": {
+    "title": "kage",
+    "description": "Kafka offset, lag and metadata monitoring API.",
+    "version": "1"
+  },
+  "servers": [{"url": "/v1"}],
+  "security": [{}, {"basicAuth": []}, {"bearerAuth": []}],
+  "paths": {
+    "/health": {
+      "get": {
+        "summary": "Get the health of kage",
+        "security": [],
+        "responses": {
+          "200": {"description": "Kage is healthy"},
+          "500": {"description": "Kage is unhealthy"}
+        }
+      }
+    },
+    "/brokers": {
+      "get": {
+        "summary": "Get the state of all known brokers",
+        "parameters": [{"$ref": "#/components/parameters/format"}],
+        "responses": {
+          "200": {
+            "description": "The brokers",
+            "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/BrokerStatus"}}}}
+          }
+        }
+      }
+    },
+    "/brokers/health": {
+      "get": {
+        "summary": "Get the health of the Kafka brokers",
+        "responses": {
+          "200": {"description": "All brokers are connected"},
+          "500": {"description": "A broker is not connected"}
+        }
+      }
+    },
+    "/topics": {
+      "get": {
+        "summary": "Get the offsets of all topics",
+        "parameters": [
+          {"$ref": "#/components/parameters/topic"},
+          {"name": "sort", "in": "query", "schema": {"type": "string", "enum": ["topic", "available"], "default": "topic"}},
+          {"$ref": "#/components/parameters/order"},
+          {"$ref": "#/components/parameters/limit"},
+          {"$ref": "#/components/parameters/offset"},
+          {"$ref": "#/components/parameters/format"}
+        ],
+        "responses": {
+          "200": {
+            "description": "The topic offsets",
+            "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/BrokerTopics"}}}}
+          },
+          "400": {"description": "Invalid query parameters"}
+        }
+      }
+    },
+    "/topics/{topic}": {
+      "get": {
+        "summary": "Get the offsets and metadata of a topic",
+        "parameters": [
+          {"$ref": "#/components/parameters/topicPath"},
+          {"$ref": "#/components/parameters/format"}
+        ],
+        "responses": {
+          "200": {
+            "description": "The topic offsets and metadata",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/TopicDetail"}}}
+          },
+          "404": {"description": "The topic was not found"}
+        }
+      }
+    },
+    "/topics/{topic}/consumers": {
+      "get": {
+        "summary": "Get the offsets of all consumer groups consuming a topic",
+        "parameters": [
+          {"$ref": "#/components/parameters/topicPath"},
+          {"$ref": "#/components/parameters/group"},
+          {"$ref": "#/components/parameters/minLag"},
+          {"$ref": "#/components/parameters/consumerSort"},
+          {"$ref": "#/components/parameters/order"},
+          {"$ref": "#/components/parameters/limit"},
+          {"$ref": "#/components/parameters/offset"},
+          {"$ref": "#/components/parameters/format"}
+        ],
+        "responses": {
+          "200": {
+            "description": "The consumer group offsets",
+            "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/ConsumerGroup"}}}}
+          },
+          "400": {"description": "Invalid query parameters"},
+          "404": {"description": "The topic was not found"}
+        }
+      }
+    },
+    "/metadata": {
+      "get": {
+        "summary": "Get the metadata of all topics",
+        "parameters": [
+          {"$ref": "#/components/parameters/topic"},
+          {"name": "sort", "in": "query", "schema": {"type": "string", "enum": ["topic", "partitions"], "default": "topic"}},
+          {"$ref": "#/components/parameters/order"},
+          {"$ref": "#/components/parameters/limit"},
+          {"$ref": "#/components/parameters/offset"},
+          {"$ref": "#/components/parameters/format"}
+        ],
+        "responses": {
+          "200": {
+            "description": "The topic metadata",
+            "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/TopicMetadata"}}}}
+          },
+          "400": {"description": "Invalid query parameters"}
+        }
+      }
+    },
+    "/consumers": {
+      "get": {
+        "summary": "Get the offsets of all consumer groups",
+        "parameters": [
+          {"$ref": "#/components/parameters/topic"},
+          {"$ref": "#/components/parameters/group"},
+          {"$ref": "#/components/parameters/minLag"},
+          {"$ref": "#/components/parameters/consumerSort"},
+          {"$ref": "#/components/parameters/order"},
+          {"$ref": "#/components/parameters/limit"},
+          {"$ref": "#/components/parameters/offset"},
+          {"$ref": "#/components/parameters/format"}
+        ],
+        "responses": {
+          "200": {
+            "description": "The consumer group offsets",
+            "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/ConsumerGroup"}}}}
+          },
+          "400": {"description": "Invalid query parameters"}
+        }
+      }
+    },
+    "/consumers/{group}": {
+      "get": {
+        "summary": "Get the offsets of a consumer group",
+        "parameters": [
+          {"name": "group", "in": "path", "required": true, "schema": {"type": "string"}},
+          {"$ref": "#/components/parameters/topic"},
+          {"$ref": "#/components/parameters/minLag"},
+          {"$ref": "#/components/parameters/consumerSort"},
+          {"$ref": "#/components/parameters/order"},
+          {"$ref": "#/components/parameters/limit"},
+          {"$ref": "#/components/parameters/offset"},
+          {"$ref": "#/components/parameters/format"}
+        ],
+        "responses": {
+          "200": {
+            "description": "The consumer group offsets",
+            "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/ConsumerGroup"}}}}
+          },
+          "400": {"description": "Invalid query parameters"},
+          "404": {"description": "The consumer group was not found"}
+        }
+      }
+    },
+    "/regressions": {
+      "get": {
+        "summary": "Get the log of consumer offset regressions",
+        "parameters": [{"$ref": "#/components/parameters/format"}],
+        "responses": {
+          "200": {
+            "description": "The offset regressions",
+            "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/OffsetRegression"}}}}
+          }
+        }
+      }
+    },
+    "/events": {
+      "get": {
+        "summary": "Get the log of cluster events",
+        "parameters": [{"$ref": "#/components/parameters/format"}],
+        "responses": {
+          "200": {
+            "description": "The cluster events",
+            "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/ClusterEvent"}}}}
+          }
+        }
+      }
+    },
+    "/alerts": {
+      "get": {
+        "summary": "Get the pending and firing alerts",
+        "parameters": [{"$ref": "#/components/parameters/format"}],
+        "responses": {
+          "200": {
+            "description": "The active alerts",
+            "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/Alert"}}}}
+          }
+        }
+      }
+    },
+    "/stream": {
+      "get": {
+        "summary": "Stream the store updates as server-sent events",
+        "description": "Each event is named after the update type and its data is a StreamUpdate.",
+        "parameters": [
+          {"$ref": "#/components/parameters/topic"},
+          {"name": "group", "in": "query", "description": "Only stream the consumer offsets of groups matching the pattern. This may contain wildcards.", "schema": {"type": "string"}}
+        ],
+        "responses": {
+          "200": {
+            "description": "The stream of updates",
+            "content": {"text/event-stream": {"schema": {"$ref": "#/components/schemas/StreamUpdate"}}}
+          }
+        }
+      }
+    },
+    "/metrics": {
+      "get": {
+        "summary": "Get the internal metrics of kage",
+        "parameters": [{"$ref": "#/components/parameters/format"}],
+        "responses": {
+          "200": {
+            "description": "The internal metrics",
+            "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/Metric"}}}}
+          }
+        }
+      }
+    },
+    "/openapi.json": {
+      "get": {
+        "summary": "Get the OpenAPI description of the API",
+        "responses": {
+          "200": {"description": "The OpenAPI description", "content": {"application/json": {}}}
+        }
+      }
+    }
+  },
+  "components": {
+    "securitySchemes": {
+      "basicAuth": {"type": "http", "scheme": "basic"},
+      "bearerAuth": {"type": "http", "scheme": "bearer"}
+    },
+    "parameters": {
+      "topicPath": {"name": "topic", "in": "path", "required": true, "schema": {"type": "string"}},
+      "topic": {"name": "topic", "in": "query", "description": "Only include topics matching the pattern. This may contain wildcards.", "schema": {"type": "string"}},
+      "group": {"name": "group", "in": "query", "description": "Only include consumer groups matching the pattern. This may contain wildcards.", "schema": {"type": "string"}},
+      "minLag": {"name": "min_lag", "in": "query", "description": "Only include consumer group topics with at least this total lag.", "schema": {"type": "integer", "format": "int64"}},
+      "consumerSort": {"name": "sort", "in": "query", "schema": {"type": "string", "enum": ["group", "topic", "lag"], "default": "group"}},
+      "order": {"name": "order", "in": "query", "schema": {"type": "string", "enum": ["asc", "desc"], "default": "asc"}},
+      "limit": {"name": "limit", "in": "query", "description": "The maximum number of items to return.", "schema": {"type": "integer", "minimum": 0}},
+      "offset": {"name": "offset", "in": "query", "description": "The number of items to skip.", "schema": {"type": "integer", "minimum": 0}},
+      "format": {"name": "format", "in": "query", "description": "The response format. Overrides the Accept header.", "schema": {"type": "string", "enum": ["json", "csv", "table"], "default": "json"}}
+    },
+    "schemas": {
+      "BrokerStatus": {
+        "type": "object",
+        "properties": {
+          "id": {"type": "integer", "format": "int32"},
+          "connected": {"type": "boolean"}
+        }
+      },
+      "BrokerTopics": {
+        "type": "object",
+        "properties": {
+          "topic": {"type": "string"},
+          "total_available": {"type": "integer", "format": "int64"},
+          "partitions": {"type": "array", "items": {"$ref": "#/components/schemas/BrokerPartition"}}
+        }
+      },
+      "BrokerPartition": {
+        "type": "object",
+        "properties": {
+          "partition": {"type": "integer"},
+          "oldest": {"type": "integer", "format": "int64"},
+          "newest": {"type": "integer", "format": "int64"},
+          "available": {"type": "integer", "format": "int64"}
+        }
+      },
+      "TopicDetail": {
+        "type": "object",
+        "properties": {
+          "topic": {"type": "string"},
+          "total_available": {"type": "integer", "format": "int64"},
+          "partitions": {"type": "array", "items": {"$ref": "#/components/schemas/TopicPartition"}}
+        }
+      },
+      "TopicPartition": {
+        "type": "object",
+        "properties": {
+          "partition": {"type": "integer"},
+          "oldest": {"type": "integer", "format": "int64"},
+          "newest": {"type": "integer", "format": "int64"},
+          "available": {"type": "integer", "format": "int64"},
+          "leader": {"type": "integer", "format": "int32"},
+          "replicas": {"type": "array", "items": {"type": "integer", "format": "int32"}},
+          "isr": {"type": "array", "items": {"type": "integer", "format": "int32"}}
+        }
+      },
+      "TopicMetadata": {
+        "type": "object",
+        "properties": {
+          "topic": {"type": "string"},
+          "partitions": {"type": "array", "items": {"$ref": "#/components/schemas/PartitionMetadata"}}
+        }
+      },
+      "PartitionMetadata": {
+        "type": "object",
+        "properties": {
+          "partition": {"type": "integer"},
+          "leader": {"type": "integer", "format": "int32"},
+          "replicas": {"type": "array", "items": {"type": "integer", "format": "int32"}},
+          "isr": {"type": "array", "items": {"type": "integer", "format": "int32"}}
+        }
+      },
+      "ConsumerGroup": {
+        "type": "object",
+        "properties": {
+          "group": {"type": "string"},
+          "topic": {"type": "string"},
+          "total_lag": {"type": "integer", "format": "int64"},
+          "partitions": {"type": "array", "items": {"$ref": "#/components/schemas/ConsumerPartition"}}
+        }
+      },
+      "ConsumerPartition": {
+        "type": "object",
+        "properties": {
+          "partition": {"type": "integer"},
+          "offset": {"type": "integer", "format": "int64"},
+          "lag": {"type": "integer", "format": "int64"}
+        }
+      },
+      "OffsetRegression": {
+        "type": "object",
+        "properties": {
+          "id": {"type": "integer", "format": "int64"},
+          "kind": {"type": "string", "enum": ["rewind", "jump"]},
+          "group": {"type": "string"},
+          "topic": {"type": "string"},
+          "partition": {"type": "integer", "format": "int32"},
+          "old_offset": {"type": "integer", "format": "int64"},
+          "new_offset": {"type": "integer", "format": "int64"},
+          "newest": {"type": "integer", "format": "int64"},
+          "timestamp": {"type": "integer", "format": "int64", "description": "Unix time in milliseconds."}
+        }
+      },
+      "ClusterEvent": {
+        "type": "object",
+        "properties": {
+          "id": {"type": "integer", "format": "int64"},
+          "type": {"type": "string", "enum": ["topic_created", "topic_deleted", "partitions_added", "leader_changed", "isr_shrink", "isr_expand", "group_appeared", "group_emptied"]},
+          "topic": {"type": "string"},
+          "partition": {"type": "integer", "format": "int32"},
+          "group": {"type": "string"},
+          "old": {"type": "array", "items": {"type": "integer", "format": "int32"}},
+          "new": {"type": "array", "items": {"type": "integer", "format": "int32"}},
+          "timestamp": {"type": "integer", "format": "int64", "description": "Unix time in milliseconds."}
+        }
+      },
+      "Alert": {
+        "type": "object",
+        "properties": {
+          "name": {"type": "string"},
+          "state": {"type": "string", "enum": ["pending", "firing"]},
+          "labels": {"type": "object", "additionalProperties": {"type": "string"}},
+          "annotations": {"type": "object", "additionalProperties": {"type": "string"}},
+          "value": {"type": "number"},
+          "active_at": {"type": "integer", "format": "int64", "description": "Unix time in milliseconds."},
+          "fired_at": {"type": "integer", "format": "int64", "description": "Unix time in milliseconds, when firing."}
+        }
+      },
+      "Metric": {
+        "type": "object",
+        "properties": {
+          "name": {"type": "string"},
+          "type": {"type": "string", "enum": ["counter", "gauge", "timer"]},
+          "tags": {"type": "object", "additionalProperties": {"type": "string"}},
+          "values": {"type": "object", "description": "Counters have a count, gauges a value and timers a count, last, mean and max in milliseconds.", "additionalProperties": {"type": "number"}}
+        }
+      },
+      "StreamUpdate": {
+        "type": "object",
+        "properties": {
+          "type": {"type": "string", "enum": ["broker_offset", "consumer_offset", "metadata"]},
+          "group": {"type": "string"},
+          "topic": {"type": "string"},
+          "partition": {
+            "oneOf": [
+              {"$ref": "#/components/schemas/BrokerPartition"},
+              {"$ref": "#/components/schemas/ConsumerPartition"},
+              {"$ref": "#/components/schemas/PartitionMetadata"}
+            ]
+          }
+        }
+      }
+    }
+  }
+}
+`
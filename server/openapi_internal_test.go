@@ -0,0 +1,32 @@
+package server
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/msales/kage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAPIDocumentCoversRoutes(t *testing.T) {
+	var doc struct {
+		Paths map[string]interface{} `json:"paths"`
+	}
+	if err := json.Unmarshal([]byte(openAPIDocument), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	params := regexp.MustCompile(`:(\w+)`)
+
+	s := New(&kage.Application{})
+	for _, route := range s.mux.Routes["GET"] {
+		if !strings.HasPrefix(route.Path, apiVersionPrefix+"/") {
+			continue
+		}
+
+		path := params.ReplaceAllString(strings.TrimPrefix(route.Path, apiVersionPrefix), "{$1}")
+		assert.Contains(t, doc.Paths, path)
+	}
+}
@@ -0,0 +1,31 @@
+package server_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/msales/kage"
+	"github.com/msales/kage/server"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAPIHandler(t *testing.T) {
+	req, err := http.NewRequest("GET", "/v1/openapi.json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+
+	srv := server.New(&kage.Application{})
+	srv.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+
+	var doc map[string]interface{}
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &doc))
+	assert.Equal(t, "3.0.0", doc["openapi"])
+}
@@ -0,0 +1,19 @@
+package server
+
+// OptFunc represents a function that configures the Server.
+type OptFunc func(s *Server)
+
+// BasicAuth configures the basic authentication credentials on the Server.
+func BasicAuth(username, password string) OptFunc {
+	return func(s *Server) {
+		s.username = username
+		s.password = password
+	}
+}
+
+// BearerToken configures the bearer authentication token on the Server.
+func BearerToken(token string) OptFunc {
+	return func(s *Server) {
+		s.token = token
+	}
+}
@@ -0,0 +1,24 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBasicAuth(t *testing.T) {
+	s := &Server{}
+
+	BasicAuth("user", "pass")(s)
+
+	assert.Equal(t, "user", s.username)
+	assert.Equal(t, "pass", s.password)
+}
+
+func TestBearerToken(t *testing.T) {
+	s := &Server{}
+
+	BearerToken("token")(s)
+
+	assert.Equal(t, "token", s.token)
+}
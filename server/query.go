@@ -0,0 +1,139 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/ryanuber/go-glob"
+)
+
+// listQuery represents the filtering, sorting and pagination of a list request.
+type listQuery struct {
+	Topic  string
+	Group  string
+	MinLag int64
+
+	Sort string
+	Desc bool
+
+	Limit  int
+	Offset int
+}
+
+// parseListQuery parses the list query parameters of a request. The first of
+// the given sort fields is used when no sort field is requested.
+func parseListQuery(r *http.Request, sorts ...string) (*listQuery, error) {
+	v := r.URL.Query()
+
+	q := &listQuery{
+		Topic: v.Get("topic"),
+		Group: v.Get("group"),
+		Sort:  v.Get("sort"),
+	}
+
+	if q.Sort == "" && len(sorts) > 0 {
+		q.Sort = sorts[0]
+	}
+	if !containsString(sorts, q.Sort) {
+		return nil, fmt.Errorf("invalid sort field \"%s\"", q.Sort)
+	}
+
+	switch v.Get("order") {
+	case "", "asc":
+	case "desc":
+		q.Desc = true
+	default:
+		return nil, fmt.Errorf("invalid order \"%s\"", v.Get("order"))
+	}
+
+	var err error
+	if s := v.Get("min_lag"); s != "" {
+		if q.MinLag, err = strconv.ParseInt(s, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid min_lag \"%s\"", s)
+		}
+	}
+
+	if s := v.Get("limit"); s != "" {
+		if q.Limit, err = strconv.Atoi(s); err != nil || q.Limit < 0 {
+			return nil, fmt.Errorf("invalid limit \"%s\"", s)
+		}
+	}
+
+	if s := v.Get("offset"); s != "" {
+		if q.Offset, err = strconv.Atoi(s); err != nil || q.Offset < 0 {
+			return nil, fmt.Errorf("invalid offset \"%s\"", s)
+		}
+	}
+
+	return q, nil
+}
+
+// MatchTopic determines if the topic matches the topic filter.
+func (q *listQuery) MatchTopic(topic string) bool {
+	return q.Topic == "" || glob.Glob(q.Topic, topic)
+}
+
+// MatchGroup determines if the group matches the group filter.
+func (q *listQuery) MatchGroup(group string) bool {
+	return q.Group == "" || glob.Glob(q.Group, group)
+}
+
+// Less orders the result of a comparison according to the requested order.
+func (q *listQuery) Less(cmp int) bool {
+	if q.Desc {
+		return cmp > 0
+	}
+
+	return cmp < 0
+}
+
+// Page returns the bounds of the requested page for a list of the given length.
+func (q *listQuery) Page(length int) (int, int) {
+	start := q.Offset
+	if start > length {
+		start = length
+	}
+
+	end := length
+	if q.Limit > 0 && start+q.Limit < end {
+		end = start + q.Limit
+	}
+
+	return start, end
+}
+
+// compareStrings compares two strings, returning -1, 0 or 1.
+func compareStrings(a, b string) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// compareInts compares two integers, returning -1, 0 or 1.
+func compareInts(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// containsString determines if the slice contains the string.
+func containsString(s []string, str string) bool {
+	for _, v := range s {
+		if v == str {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,63 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+)
+
+type offsetRegression struct {
+	ID        int64  `json:"id"`
+	Kind      string `json:"kind"`
+	Group     string `json:"group"`
+	Topic     string `json:"topic"`
+	Partition int32  `json:"partition"`
+	OldOffset int64  `json:"old_offset"`
+	NewOffset int64  `json:"new_offset"`
+	Newest    int64  `json:"newest"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+type offsetRegressionList []offsetRegression
+
+func (l offsetRegressionList) Header() []string {
+	return []string{"id", "kind", "group", "topic", "partition", "old_offset", "new_offset", "newest", "timestamp"}
+}
+
+func (l offsetRegressionList) Rows() [][]string {
+	rows := [][]string{}
+	for _, r := range l {
+		rows = append(rows, []string{
+			fmt.Sprint(r.ID),
+			r.Kind,
+			r.Group,
+			r.Topic,
+			fmt.Sprint(r.Partition),
+			fmt.Sprint(r.OldOffset),
+			fmt.Sprint(r.NewOffset),
+			fmt.Sprint(r.Newest),
+			fmt.Sprint(r.Timestamp),
+		})
+	}
+
+	return rows
+}
+
+// RegressionsHandler handles requests for consumer offset regressions.
+func (s *Server) RegressionsHandler(w http.ResponseWriter, r *http.Request) {
+	regressions := offsetRegressionList{}
+	for _, regression := range s.Store.OffsetRegressions() {
+		regressions = append(regressions, offsetRegression{
+			ID:        regression.ID,
+			Kind:      regression.Kind,
+			Group:     regression.Group,
+			Topic:     regression.Topic,
+			Partition: regression.Partition,
+			OldOffset: regression.OldOffset,
+			NewOffset: regression.NewOffset,
+			Newest:    regression.NewestOffset,
+			Timestamp: regression.Timestamp,
+		})
+	}
+
+	s.write(w, r, regressions)
+}
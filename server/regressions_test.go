@@ -0,0 +1,38 @@
+package server_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/msales/kage"
+	"github.com/msales/kage/server"
+	"github.com/msales/kage/store"
+	"github.com/msales/kage/testutil/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegressionsHandler(t *testing.T) {
+	req, err := http.NewRequest("GET", "/regressions", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+
+	or := store.OffsetRegressions{
+		{ID: 1, Kind: store.RegressionRewind, Group: "foo", Topic: "test", Partition: 0, OldOffset: 1000, NewOffset: 100, NewestOffset: 1200, Timestamp: 0},
+	}
+
+	store := new(mocks.MockStore)
+	store.On("OffsetRegressions").Return(or)
+
+	app := &kage.Application{Store: store}
+
+	srv := server.New(app)
+	srv.ServeHTTP(rr, req)
+
+	want := "[{\"id\":1,\"kind\":\"rewind\",\"group\":\"foo\",\"topic\":\"test\",\"partition\":0,\"old_offset\":1000,\"new_offset\":100,\"newest\":1200,\"timestamp\":0}]"
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, want, rr.Body.String())
+}
@@ -9,28 +9,52 @@ import (
 	"github.com/msales/kage"
 )
 
+// apiVersionPrefix is the path prefix of the current API version.
+const apiVersionPrefix = "/v1"
+
 // Server represents an http server.
 type Server struct {
 	*kage.Application
 
-	mux *bone.Mux
+	mux      *bone.Mux
+	shutdown chan struct{}
+
+	username string
+	password string
+	token    string
 }
 
 // New creates a new instance of Server.
-func New(app *kage.Application) *Server {
+func New(app *kage.Application, opts ...OptFunc) *Server {
 	s := &Server{
 		Application: app,
 		mux:         bone.New(),
+		shutdown:    make(chan struct{}),
 	}
 
-	s.mux.GetFunc("/brokers", s.BrokersHandler)
-	s.mux.GetFunc("/brokers/health", s.BrokersHealthHandler)
-	s.mux.GetFunc("/metadata", s.MetadataHandler)
-	s.mux.GetFunc("/topics", s.TopicsHandler)
-	s.mux.GetFunc("/consumers", s.ConsumerGroupsHandler)
-	s.mux.GetFunc("/consumers/:group", s.ConsumerGroupHandler)
+	for _, o := range opts {
+		o(s)
+	}
 
-	s.mux.GetFunc("/health", s.HealthHandler)
+	// The unversioned paths are kept as aliases of the v1 API
+	for _, prefix := range []string{apiVersionPrefix, ""} {
+		s.mux.GetFunc(prefix+"/brokers", s.BrokersHandler)
+		s.mux.GetFunc(prefix+"/brokers/health", s.BrokersHealthHandler)
+		s.mux.GetFunc(prefix+"/metadata", s.MetadataHandler)
+		s.mux.GetFunc(prefix+"/topics", s.TopicsHandler)
+		s.mux.GetFunc(prefix+"/topics/:topic", s.TopicHandler)
+		s.mux.GetFunc(prefix+"/topics/:topic/consumers", s.TopicConsumersHandler)
+		s.mux.GetFunc(prefix+"/consumers", s.ConsumerGroupsHandler)
+		s.mux.GetFunc(prefix+"/consumers/:group", s.ConsumerGroupHandler)
+		s.mux.GetFunc(prefix+"/regressions", s.RegressionsHandler)
+		s.mux.GetFunc(prefix+"/events", s.EventsHandler)
+		s.mux.GetFunc(prefix+"/alerts", s.AlertsHandler)
+		s.mux.GetFunc(prefix+"/stream", s.StreamHandler)
+		s.mux.GetFunc(prefix+"/metrics", s.MetricsHandler)
+		s.mux.GetFunc(prefix+"/health", s.HealthHandler)
+	}
+	s.mux.GetFunc(apiVersionPrefix+"/openapi.json", s.OpenAPIHandler)
+	s.mux.GetFunc("/ui", s.UIHandler)
 
 	return s
 }
@@ -38,17 +62,42 @@ func New(app *kage.Application) *Server {
 // ServeHTTP dispatches the request to the handler whose
 // pattern most closely matches the request URL.
 func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !s.authenticate(r) {
+		s.unauthorized(w)
+		return
+	}
+
 	s.mux.ServeHTTP(w, r)
 }
 
+// Close ends all open streams.
+func (s *Server) Close() {
+	close(s.shutdown)
+}
+
 type brokerStatus struct {
 	ID        int32 `json:"id"`
 	Connected bool  `json:"connected"`
 }
 
+type brokerStatuses []brokerStatus
+
+func (l brokerStatuses) Header() []string {
+	return []string{"id", "connected"}
+}
+
+func (l brokerStatuses) Rows() [][]string {
+	rows := [][]string{}
+	for _, b := range l {
+		rows = append(rows, []string{fmt.Sprint(b.ID), fmt.Sprint(b.Connected)})
+	}
+
+	return rows
+}
+
 // BrokersHandler handles requests for brokers status.
 func (s *Server) BrokersHandler(w http.ResponseWriter, r *http.Request) {
-	brokers := []brokerStatus{}
+	brokers := brokerStatuses{}
 	for _, b := range s.Monitor.Brokers() {
 		brokers = append(brokers, brokerStatus{
 			ID:        b.ID,
@@ -56,7 +105,7 @@ func (s *Server) BrokersHandler(w http.ResponseWriter, r *http.Request) {
 		})
 	}
 
-	s.writeJSON(w, brokers)
+	s.write(w, r, brokers)
 }
 
 // BrokersHealthHandler handles requests for brokers health.
@@ -87,5 +136,6 @@ func (s *Server) writeJSON(w http.ResponseWriter, v interface{}) {
 		return
 	}
 
+	w.Header().Set("Content-Type", "application/json")
 	w.Write(data)
 }
@@ -98,3 +98,27 @@ func TestHealthFail(t *testing.T) {
 
 	assert.Equal(t, http.StatusInternalServerError, rr.Code)
 }
+
+func TestVersionedPaths(t *testing.T) {
+	tests := []string{"/v1/brokers", "/brokers"}
+
+	for _, path := range tests {
+		req, err := http.NewRequest("GET", path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rr := httptest.NewRecorder()
+
+		monitor := new(mocks.MockMonitor)
+		monitor.On("Brokers").Return([]kafka.Broker{{ID: 1, Connected: true}})
+
+		app := &kage.Application{Monitor: monitor}
+
+		srv := server.New(app)
+		srv.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code, path)
+		assert.Equal(t, "[{\"id\":1,\"connected\":true}]", rr.Body.String(), path)
+	}
+}
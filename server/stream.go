@@ -0,0 +1,114 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/msales/kage/store"
+	"github.com/ryanuber/go-glob"
+)
+
+// keepAliveInterval is the interval between keep alive comments on idle streams.
+const keepAliveInterval = 15 * time.Second
+
+type streamUpdate struct {
+	Type      string      `json:"type"`
+	Group     string      `json:"group,omitempty"`
+	Topic     string      `json:"topic"`
+	Partition interface{} `json:"partition"`
+}
+
+// StreamHandler handles requests for a stream of store updates as server-sent events.
+//
+// The stream can be filtered with the "topic" and "group" query parameters,
+// which may contain wildcards. Filtering by group only streams consumer offsets.
+func (s *Server) StreamHandler(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	topic := r.URL.Query().Get("topic")
+	group := r.URL.Query().Get("group")
+
+	updates := s.Store.Subscribe()
+	defer s.Store.Unsubscribe(updates)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+
+		case <-s.shutdown:
+			return
+
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+			flusher.Flush()
+
+		case u := <-updates:
+			if topic != "" && !glob.Glob(topic, u.Topic) {
+				continue
+			}
+			if group != "" && (u.Type != store.UpdateConsumerOffset || !glob.Glob(group, u.Group)) {
+				continue
+			}
+
+			data, err := json.Marshal(createStreamUpdate(u))
+			if err != nil {
+				s.Logger.Error(fmt.Sprintf("server: error writing stream: %s", err))
+				continue
+			}
+
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", u.Type, data)
+			flusher.Flush()
+		}
+	}
+}
+
+func createStreamUpdate(u *store.Update) streamUpdate {
+	su := streamUpdate{
+		Type:  u.Type,
+		Group: u.Group,
+		Topic: u.Topic,
+	}
+
+	switch u.Type {
+	case store.UpdateBrokerOffset:
+		su.Partition = brokerPartition{
+			Partition: int(u.Partition),
+			Oldest:    u.BrokerOffset.OldestOffset,
+			Newest:    u.BrokerOffset.NewestOffset,
+			Available: u.BrokerOffset.NewestOffset - u.BrokerOffset.OldestOffset,
+		}
+
+	case store.UpdateConsumerOffset:
+		su.Partition = consumerPartition{
+			Partition: int(u.Partition),
+			Offset:    u.ConsumerOffset.Offset,
+			Lag:       u.ConsumerOffset.Lag,
+		}
+
+	case store.UpdateMetadata:
+		su.Partition = partitionMetadata{
+			Partition: int(u.Partition),
+			Leader:    u.Metadata.Leader,
+			Replicas:  u.Metadata.Replicas,
+			Isr:       u.Metadata.Isr,
+		}
+	}
+
+	return su
+}
@@ -0,0 +1,96 @@
+package server_test
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/msales/kage"
+	"github.com/msales/kage/server"
+	"github.com/msales/kage/store"
+	"github.com/msales/kage/testutil/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamHandler(t *testing.T) {
+	updates := make(chan *store.Update, 10)
+	updates <- &store.Update{
+		Type:         store.UpdateBrokerOffset,
+		Topic:        "test",
+		Partition:    0,
+		BrokerOffset: &store.BrokerOffset{OldestOffset: 0, NewestOffset: 100},
+	}
+	updates <- &store.Update{
+		Type:           store.UpdateConsumerOffset,
+		Group:          "bar",
+		Topic:          "test",
+		Partition:      0,
+		ConsumerOffset: &store.ConsumerOffset{Offset: 50, Lag: 50},
+	}
+	updates <- &store.Update{
+		Type:           store.UpdateConsumerOffset,
+		Group:          "foo",
+		Topic:          "test",
+		Partition:      0,
+		ConsumerOffset: &store.ConsumerOffset{Offset: 10, Lag: 90},
+	}
+
+	store := new(mocks.MockStore)
+	store.On("Subscribe").Return(updates)
+	store.On("Unsubscribe", updates).Return()
+
+	app := &kage.Application{Store: store}
+
+	ts := httptest.NewServer(server.New(app))
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/stream?group=f*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	lines := []string{}
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+		if strings.HasPrefix(scanner.Text(), "data:") {
+			break
+		}
+	}
+
+	want := []string{
+		"event: consumer_offset",
+		"data: {\"type\":\"consumer_offset\",\"group\":\"foo\",\"topic\":\"test\",\"partition\":{\"partition\":0,\"offset\":10,\"lag\":90}}",
+	}
+	assert.Equal(t, want, lines)
+}
+
+func TestStreamHandler_Close(t *testing.T) {
+	updates := make(chan *store.Update)
+
+	store := new(mocks.MockStore)
+	store.On("Subscribe").Return(updates)
+	store.On("Unsubscribe", updates).Return()
+
+	app := &kage.Application{Store: store}
+
+	srv := server.New(app)
+	srv.Close()
+
+	req, err := http.NewRequest("GET", "/stream", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	srv.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	store.AssertExpectations(t)
+}
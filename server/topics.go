@@ -1,7 +1,12 @@
 package server
 
 import (
+	"fmt"
 	"net/http"
+	"sort"
+
+	"github.com/go-zoo/bone"
+	"github.com/msales/kage/store"
 )
 
 type brokerTopics struct {
@@ -17,12 +22,77 @@ type brokerPartition struct {
 	Available int64 `json:"available"`
 }
 
+type brokerTopicsList []brokerTopics
+
+func (l brokerTopicsList) Header() []string {
+	return []string{"topic", "partition", "oldest", "newest", "available"}
+}
+
+func (l brokerTopicsList) Rows() [][]string {
+	rows := [][]string{}
+	for _, t := range l {
+		for _, p := range t.Partitions {
+			rows = append(rows, []string{t.Topic, fmt.Sprint(p.Partition), fmt.Sprint(p.Oldest), fmt.Sprint(p.Newest), fmt.Sprint(p.Available)})
+		}
+	}
+
+	return rows
+}
+
+type topicDetail struct {
+	Topic          string           `json:"topic"`
+	TotalAvailable int64            `json:"total_available"`
+	Partitions     []topicPartition `json:"partitions"`
+}
+
+type topicPartition struct {
+	Partition int     `json:"partition"`
+	Oldest    int64   `json:"oldest"`
+	Newest    int64   `json:"newest"`
+	Available int64   `json:"available"`
+	Leader    int32   `json:"leader"`
+	Replicas  []int32 `json:"replicas"`
+	Isr       []int32 `json:"isr"`
+}
+
+func (t topicDetail) Header() []string {
+	return []string{"topic", "partition", "oldest", "newest", "available", "leader", "replicas", "isr"}
+}
+
+func (t topicDetail) Rows() [][]string {
+	rows := [][]string{}
+	for _, p := range t.Partitions {
+		rows = append(rows, []string{
+			t.Topic,
+			fmt.Sprint(p.Partition),
+			fmt.Sprint(p.Oldest),
+			fmt.Sprint(p.Newest),
+			fmt.Sprint(p.Available),
+			fmt.Sprint(p.Leader),
+			formatInts(p.Replicas),
+			formatInts(p.Isr),
+		})
+	}
+
+	return rows
+}
+
 // TopicsHandler handles requests for topic offsets.
 func (s *Server) TopicsHandler(w http.ResponseWriter, r *http.Request) {
+	q, err := parseListQuery(r, "topic", "available")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	offsets := s.Store.BrokerOffsets()
 
-	topics := []brokerTopics{}
+	topics := brokerTopicsList{}
 	for topic, partitions := range offsets {
+		if !q.MatchTopic(topic) {
+			continue
+		}
+
 		bt := brokerTopics{
 			Topic:      topic,
 			Partitions: make([]brokerPartition, len(partitions)),
@@ -47,5 +117,91 @@ func (s *Server) TopicsHandler(w http.ResponseWriter, r *http.Request) {
 		topics = append(topics, bt)
 	}
 
-	s.writeJSON(w, topics)
+	sort.SliceStable(topics, func(i, j int) bool {
+		cmp := 0
+		if q.Sort == "available" {
+			cmp = compareInts(topics[i].TotalAvailable, topics[j].TotalAvailable)
+		}
+		if cmp == 0 {
+			cmp = compareStrings(topics[i].Topic, topics[j].Topic)
+		}
+
+		return q.Less(cmp)
+	})
+
+	start, end := q.Page(len(topics))
+	s.write(w, r, topics[start:end])
+}
+
+// TopicHandler handles requests for a topic offsets and metadata.
+func (s *Server) TopicHandler(w http.ResponseWriter, r *http.Request) {
+	topic := bone.GetValue(r, "topic")
+
+	offsets, ok := s.Store.BrokerOffsets()[topic]
+	metadata, mok := s.Store.BrokerMetadata()[topic]
+	if !ok && !mok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	count := len(offsets)
+	if len(metadata) > count {
+		count = len(metadata)
+	}
+
+	td := topicDetail{
+		Topic:      topic,
+		Partitions: make([]topicPartition, count),
+	}
+
+	for i := range td.Partitions {
+		tp := topicPartition{Partition: i}
+
+		if i < len(offsets) && offsets[i] != nil {
+			tp.Oldest = offsets[i].OldestOffset
+			tp.Newest = offsets[i].NewestOffset
+			tp.Available = offsets[i].NewestOffset - offsets[i].OldestOffset
+		}
+
+		if i < len(metadata) && metadata[i] != nil {
+			tp.Leader = metadata[i].Leader
+			tp.Replicas = metadata[i].Replicas
+			tp.Isr = metadata[i].Isr
+		}
+
+		td.TotalAvailable += tp.Available
+		td.Partitions[i] = tp
+	}
+
+	s.write(w, r, td)
+}
+
+// TopicConsumersHandler handles requests for the consumer group offsets of a topic.
+func (s *Server) TopicConsumersHandler(w http.ResponseWriter, r *http.Request) {
+	q, err := parseListQuery(r, consumerGroupSorts...)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	topic := bone.GetValue(r, "topic")
+
+	_, ok := s.Store.BrokerOffsets()[topic]
+	_, mok := s.Store.BrokerMetadata()[topic]
+	if !ok && !mok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	groups := []consumerGroup{}
+	for group, topics := range s.Store.ConsumerOffsets() {
+		partitions, ok := topics[topic]
+		if !ok {
+			continue
+		}
+
+		groups = append(groups, createConsumerGroup(group, map[string][]*store.ConsumerOffset{topic: partitions})...)
+	}
+
+	s.write(w, r, queryConsumerGroups(q, groups))
 }
@@ -36,3 +36,143 @@ func TestTopicsHandler(t *testing.T) {
 	assert.Equal(t, http.StatusOK, rr.Code)
 	assert.Equal(t, want, rr.Body.String())
 }
+
+func TestTopicHandler(t *testing.T) {
+	req, err := http.NewRequest("GET", "/topics/test", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+
+	bo := store.BrokerOffsets{
+		"test": []*store.BrokerOffset{{OldestOffset: 0, NewestOffset: 100, Timestamp: 0}},
+	}
+	bm := store.BrokerMetadata{
+		"test": []*store.Metadata{{Leader: 1, Replicas: []int32{1, 2}, Isr: []int32{1, 2}, Timestamp: 0}},
+	}
+
+	store := new(mocks.MockStore)
+	store.On("BrokerOffsets").Return(bo)
+	store.On("BrokerMetadata").Return(bm)
+
+	app := &kage.Application{Store: store}
+
+	srv := server.New(app)
+	srv.ServeHTTP(rr, req)
+
+	want := "{\"topic\":\"test\",\"total_available\":100,\"partitions\":[{\"partition\":0,\"oldest\":0,\"newest\":100,\"available\":100,\"leader\":1,\"replicas\":[1,2],\"isr\":[1,2]}]}"
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, want, rr.Body.String())
+}
+
+func TestTopicHandler_NotFound(t *testing.T) {
+	req, err := http.NewRequest("GET", "/topics/none", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+
+	bo := store.BrokerOffsets{}
+	bm := store.BrokerMetadata{}
+
+	store := new(mocks.MockStore)
+	store.On("BrokerOffsets").Return(bo)
+	store.On("BrokerMetadata").Return(bm)
+
+	app := &kage.Application{Store: store}
+
+	srv := server.New(app)
+	srv.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestTopicConsumersHandler(t *testing.T) {
+	req, err := http.NewRequest("GET", "/topics/test/consumers", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+
+	bo := store.BrokerOffsets{
+		"test": []*store.BrokerOffset{{OldestOffset: 0, NewestOffset: 100, Timestamp: 0}},
+	}
+	bm := store.BrokerMetadata{}
+	co := store.ConsumerOffsets{
+		"foo": map[string][]*store.ConsumerOffset{
+			"test":  {{Offset: 0, Lag: 100, Timestamp: 0}},
+			"other": {{Offset: 0, Lag: 10, Timestamp: 0}},
+		},
+		"bar": map[string][]*store.ConsumerOffset{
+			"other": {{Offset: 0, Lag: 10, Timestamp: 0}},
+		},
+	}
+
+	store := new(mocks.MockStore)
+	store.On("BrokerOffsets").Return(bo)
+	store.On("BrokerMetadata").Return(bm)
+	store.On("ConsumerOffsets").Return(co)
+
+	app := &kage.Application{Store: store}
+
+	srv := server.New(app)
+	srv.ServeHTTP(rr, req)
+
+	want := "[{\"group\":\"foo\",\"topic\":\"test\",\"total_lag\":100,\"partitions\":[{\"partition\":0,\"offset\":0,\"lag\":100}]}]"
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, want, rr.Body.String())
+}
+
+func TestTopicConsumersHandler_NotFound(t *testing.T) {
+	req, err := http.NewRequest("GET", "/topics/none/consumers", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+
+	bo := store.BrokerOffsets{}
+	bm := store.BrokerMetadata{}
+
+	store := new(mocks.MockStore)
+	store.On("BrokerOffsets").Return(bo)
+	store.On("BrokerMetadata").Return(bm)
+
+	app := &kage.Application{Store: store}
+
+	srv := server.New(app)
+	srv.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestTopicsHandler_Query(t *testing.T) {
+	req, err := http.NewRequest("GET", "/topics?topic=test-*&sort=available&order=desc", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+
+	bo := store.BrokerOffsets{
+		"test-a": []*store.BrokerOffset{{OldestOffset: 0, NewestOffset: 10, Timestamp: 0}},
+		"test-b": []*store.BrokerOffset{{OldestOffset: 0, NewestOffset: 100, Timestamp: 0}},
+		"other":  []*store.BrokerOffset{{OldestOffset: 0, NewestOffset: 1000, Timestamp: 0}},
+	}
+
+	store := new(mocks.MockStore)
+	store.On("BrokerOffsets").Return(bo)
+
+	app := &kage.Application{Store: store}
+
+	srv := server.New(app)
+	srv.ServeHTTP(rr, req)
+
+	want := "[{\"topic\":\"test-b\",\"total_available\":100,\"partitions\":[{\"partition\":0,\"oldest\":0,\"newest\":100,\"available\":100}]}," +
+		"{\"topic\":\"test-a\",\"total_available\":10,\"partitions\":[{\"partition\":0,\"oldest\":0,\"newest\":10,\"available\":10}]}]"
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, want, rr.Body.String())
+}
@@ -0,0 +1,192 @@
+package server
+
+import (
+	"net/http"
+)
+
+// UIHandler handles requests for the web dashboard.
+func (s *Server) UIHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(uiPage))
+}
+
+// uiPage is the single page web dashboard. It is built only on the JSON endpoints.
+const uiPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>kage</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 0; color: #222; background: #f6f7f9; }
+header { background: #263238; color: #fff; padding: 12px 24px; display: flex; align-items: center; gap: 24px; }
+header h1 { font-size: 20px; margin: 0; }
+header nav a { color: #b0bec5; margin-right: 16px; text-decoration: none; cursor: pointer; }
+header nav a.active { color: #fff; font-weight: bold; }
+header input { margin-left: auto; padding: 4px 8px; border-radius: 3px; border: 0; width: 240px; }
+main { padding: 16px 24px; }
+section { background: #fff; border: 1px solid #dde1e6; border-radius: 4px; margin-bottom: 16px; }
+section h2 { font-size: 15px; margin: 0; padding: 10px 12px; border-bottom: 1px solid #dde1e6; cursor: pointer; display: flex; gap: 16px; }
+section h2 span.meta { color: #667; font-weight: normal; margin-left: auto; }
+table { border-collapse: collapse; width: 100%; font-size: 13px; }
+th, td { text-align: right; padding: 4px 12px; border-bottom: 1px solid #f0f1f3; }
+th:first-child, td:first-child { text-align: left; }
+tr.warn td { background: #fff4e5; }
+.hidden { display: none; }
+.lag { font-weight: bold; }
+.status { color: #667; font-size: 12px; padding: 0 24px; }
+.bad { color: #c62828; }
+</style>
+</head>
+<body>
+<header>
+  <h1>kage</h1>
+  <nav>
+    <a id="nav-consumers" class="active" onclick="show('consumers')">Consumer groups</a>
+    <a id="nav-topics" onclick="show('topics')">Topics</a>
+  </nav>
+  <input id="filter" type="search" placeholder="Filter by group or topic" oninput="render()">
+</header>
+<p class="status" id="status">Loading...</p>
+<main>
+  <div id="consumers"></div>
+  <div id="topics" class="hidden"></div>
+</main>
+<script>
+var view = "consumers";
+var data = {consumers: [], topics: [], metadata: []};
+var expanded = {};
+
+function esc(s) {
+  return String(s).replace(/[&<>"']/g, function (c) {
+    return {"&": "&amp;", "<": "&lt;", ">": "&gt;", "\"": "&quot;", "'": "&#39;"}[c];
+  });
+}
+
+function show(name) {
+  view = name;
+  ["consumers", "topics"].forEach(function (v) {
+    document.getElementById(v).className = v === name ? "" : "hidden";
+    document.getElementById("nav-" + v).className = v === name ? "active" : "";
+  });
+  render();
+}
+
+document.addEventListener("click", function (e) {
+  var h = e.target.closest("h2[data-key]");
+  if (h) {
+    expanded[h.dataset.key] = !expanded[h.dataset.key];
+    render();
+  }
+});
+
+function matches(s) {
+  var f = document.getElementById("filter").value.toLowerCase();
+  return f === "" || s.toLowerCase().indexOf(f) !== -1;
+}
+
+function load(path) {
+  return fetch(path, {credentials: "same-origin"}).then(function (r) {
+    if (!r.ok) {
+      throw new Error(path + ": " + r.status);
+    }
+    return r.json();
+  });
+}
+
+function refresh() {
+  Promise.all([load("v1/consumers"), load("v1/topics"), load("v1/metadata")]).then(function (res) {
+    data.consumers = res[0] || [];
+    data.topics = res[1] || [];
+    data.metadata = res[2] || [];
+    document.getElementById("status").textContent = "Updated " + new Date().toLocaleTimeString();
+    document.getElementById("status").className = "status";
+    render();
+  }).catch(function (err) {
+    document.getElementById("status").textContent = "Error: " + err.message;
+    document.getElementById("status").className = "status bad";
+  });
+}
+
+function renderConsumers() {
+  var groups = data.consumers.filter(function (g) {
+    return matches(g.group) || matches(g.topic);
+  });
+  groups.sort(function (a, b) {
+    return b.total_lag - a.total_lag || a.group.localeCompare(b.group) || a.topic.localeCompare(b.topic);
+  });
+
+  var html = "";
+  groups.forEach(function (g) {
+    var key = "c:" + g.group + ":" + g.topic;
+    html += "<section><h2 data-key=\"" + esc(key) + "\">" +
+      esc(g.group) + " <span>" + esc(g.topic) + "</span>" +
+      "<span class=\"meta\">lag <span class=\"lag\">" + g.total_lag + "</span> &middot; " + g.partitions.length + " partitions</span></h2>";
+    if (expanded[key]) {
+      html += "<table><tr><th>Partition</th><th>Offset</th><th>Lag</th></tr>";
+      g.partitions.forEach(function (p) {
+        html += "<tr" + (p.lag > 0 ? " class=\"warn\"" : "") + "><td>" + p.partition + "</td><td>" + p.offset + "</td><td>" + p.lag + "</td></tr>";
+      });
+      html += "</table>";
+    }
+    html += "</section>";
+  });
+  document.getElementById("consumers").innerHTML = html || "<p>No consumer groups.</p>";
+}
+
+function renderTopics() {
+  var offsets = {};
+  data.topics.forEach(function (t) {
+    offsets[t.topic] = t;
+  });
+
+  var topics = data.metadata.filter(function (t) {
+    return matches(t.topic);
+  });
+  topics.sort(function (a, b) {
+    return a.topic.localeCompare(b.topic);
+  });
+
+  var html = "";
+  topics.forEach(function (t) {
+    var key = "t:" + t.topic;
+    var o = offsets[t.topic] || {total_available: 0, partitions: []};
+    t.partitions.forEach(function (p) {
+      p.replicas = p.replicas || [];
+      p.isr = p.isr || [];
+    });
+    var underReplicated = t.partitions.filter(function (p) {
+      return p.isr.length < p.replicas.length;
+    }).length;
+    html += "<section><h2 data-key=\"" + esc(key) + "\">" + esc(t.topic) +
+      "<span class=\"meta\">" + t.partitions.length + " partitions &middot; " + o.total_available + " available" +
+      (underReplicated > 0 ? " &middot; <span class=\"bad\">" + underReplicated + " under-replicated</span>" : "") + "</span></h2>";
+    if (expanded[key]) {
+      html += "<table><tr><th>Partition</th><th>Leader</th><th>Replicas</th><th>ISR</th><th>Oldest</th><th>Newest</th><th>Available</th></tr>";
+      t.partitions.forEach(function (p) {
+        var po = o.partitions[p.partition] || {oldest: "", newest: "", available: ""};
+        html += "<tr" + (p.isr.length < p.replicas.length || p.leader < 0 ? " class=\"warn\"" : "") + "><td>" + p.partition + "</td><td>" + p.leader +
+          "</td><td>" + p.replicas.join(",") + "</td><td>" + p.isr.join(",") + "</td><td>" + po.oldest +
+          "</td><td>" + po.newest + "</td><td>" + po.available + "</td></tr>";
+      });
+      html += "</table>";
+    }
+    html += "</section>";
+  });
+  document.getElementById("topics").innerHTML = html || "<p>No topics.</p>";
+}
+
+function render() {
+  if (view === "consumers") {
+    renderConsumers();
+  } else {
+    renderTopics();
+  }
+}
+
+refresh();
+setInterval(refresh, 10000);
+</script>
+</body>
+</html>
+`
@@ -4,6 +4,23 @@ import (
 	"errors"
 	"sync"
 	"time"
+
+	"github.com/msales/kage/metrics"
+	"github.com/msales/kage/utils"
+)
+
+const (
+	// stateChannelSize is the capacity of the state channel.
+	stateChannelSize = 10000
+
+	// maxRegressions is the number of offset regressions kept in the log.
+	maxRegressions = 1000
+
+	// maxEvents is the number of cluster events kept in the log.
+	maxEvents = 1000
+
+	// subscriberBuffer is the number of updates buffered for each subscriber.
+	subscriberBuffer = 1000
 )
 
 // State represents the state of the store.
@@ -16,6 +33,14 @@ type State struct {
 
 	metadata     BrokerMetadata
 	metadataLock sync.RWMutex
+
+	regressions    OffsetRegressions
+	regressionID   int64
+	regressionLock sync.RWMutex
+
+	events    ClusterEvents
+	eventID   int64
+	eventLock sync.RWMutex
 }
 
 // MemoryStore represents an in memory data store.
@@ -25,15 +50,32 @@ type MemoryStore struct {
 	shutdown      chan struct{}
 
 	stateCh chan interface{}
+
+	subscribers    map[chan *Update]struct{}
+	subscriberLock sync.RWMutex
+
+	metrics *metrics.Registry
 }
 
 // New creates and returns a new MemoryStore.
-func New() (*MemoryStore, error) {
+func New(opts ...MemoryStoreFunc) (*MemoryStore, error) {
 	m := &MemoryStore{
-		shutdown: make(chan struct{}),
-		stateCh:  make(chan interface{}, 10000),
+		shutdown:    make(chan struct{}),
+		stateCh:     make(chan interface{}, stateChannelSize),
+		subscribers: make(map[chan *Update]struct{}),
+	}
+
+	for _, o := range opts {
+		o(m)
 	}
 
+	m.metrics.GaugeFunc("store.channel.depth", func() float64 {
+		return float64(len(m.stateCh))
+	})
+	m.metrics.GaugeFunc("store.channel.capacity", func() float64 {
+		return float64(cap(m.stateCh))
+	})
+
 	// Initialise the cluster offsets
 	m.state = &State{
 		broker:   make(BrokerOffsets),
@@ -77,7 +119,11 @@ func (m *MemoryStore) SetState(v interface{}) error {
 	case *BrokerPartitionMetadata:
 		m.addMetadata(v.(*BrokerPartitionMetadata))
 
+	case *ClusterEvent:
+		m.addClusterEvent(v.(*ClusterEvent))
+
 	default:
+		m.metrics.Counter("store.objects.unknown").Inc(1)
 		return errors.New("store: unknown state object")
 	}
 
@@ -153,9 +199,10 @@ func (m *MemoryStore) BrokerMetadata() BrokerMetadata {
 			}
 
 			snapshot[topic][partition] = &Metadata{
-				Leader:   metadata.Leader,
-				Replicas: make([]int32, len(metadata.Replicas)),
-				Isr:      make([]int32, len(metadata.Isr)),
+				Leader:    metadata.Leader,
+				Replicas:  make([]int32, len(metadata.Replicas)),
+				Isr:       make([]int32, len(metadata.Isr)),
+				Timestamp: metadata.Timestamp,
 			}
 			copy(snapshot[topic][partition].Replicas, metadata.Replicas)
 			copy(snapshot[topic][partition].Isr, metadata.Isr)
@@ -165,12 +212,44 @@ func (m *MemoryStore) BrokerMetadata() BrokerMetadata {
 	return snapshot
 }
 
+// OffsetRegressions returns a snapshot of the consumer offset regression log.
+func (m *MemoryStore) OffsetRegressions() OffsetRegressions {
+	m.state.regressionLock.RLock()
+	defer m.state.regressionLock.RUnlock()
+
+	snapshot := make(OffsetRegressions, len(m.state.regressions))
+	for i, r := range m.state.regressions {
+		regression := *r
+		snapshot[i] = &regression
+	}
+
+	return snapshot
+}
+
+// ClusterEvents returns a snapshot of the cluster event log.
+func (m *MemoryStore) ClusterEvents() ClusterEvents {
+	m.state.eventLock.RLock()
+	defer m.state.eventLock.RUnlock()
+
+	snapshot := make(ClusterEvents, len(m.state.events))
+	for i, e := range m.state.events {
+		event := *e
+		event.Old = make([]int32, len(e.Old))
+		event.New = make([]int32, len(e.New))
+		copy(event.Old, e.Old)
+		copy(event.New, e.New)
+		snapshot[i] = &event
+	}
+
+	return snapshot
+}
+
 // CleanConsumerOffsets cleans old offsets from the MemoryStore.
 func (m *MemoryStore) CleanConsumerOffsets() {
 	m.state.consumerLock.Lock()
 	defer m.state.consumerLock.Unlock()
 
-	ts := time.Now().Unix() * 1000
+	ts := utils.Millis(time.Now())
 	for group, topics := range m.state.consumer {
 		for topic, partitions := range topics {
 			maxDuration := int64(0)
@@ -202,6 +281,27 @@ func (m *MemoryStore) Channel() chan interface{} {
 	return m.stateCh
 }
 
+// Subscribe returns a channel receiving the changes committed to the MemoryStore.
+//
+// Updates are dropped when the subscriber does not keep up.
+func (m *MemoryStore) Subscribe() chan *Update {
+	m.subscriberLock.Lock()
+	defer m.subscriberLock.Unlock()
+
+	ch := make(chan *Update, subscriberBuffer)
+	m.subscribers[ch] = struct{}{}
+
+	return ch
+}
+
+// Unsubscribe stops sending updates to the given channel.
+func (m *MemoryStore) Unsubscribe(ch chan *Update) {
+	m.subscriberLock.Lock()
+	defer m.subscriberLock.Unlock()
+
+	delete(m.subscribers, ch)
+}
+
 // Close gracefully stops the MemoryStore.
 func (m *MemoryStore) Close() {
 	m.cleanupTicker.Stop()
@@ -209,6 +309,11 @@ func (m *MemoryStore) Close() {
 }
 
 func (m *MemoryStore) addBrokerOffset(o *BrokerPartitionOffset) {
+	if !o.Oldest {
+		// Deferred before the unlock so it runs once the broker lock is released
+		defer m.checkOffsetJumps(o)
+	}
+
 	m.state.brokerLock.Lock()
 	defer m.state.brokerLock.Unlock()
 
@@ -237,11 +342,69 @@ func (m *MemoryStore) addBrokerOffset(o *BrokerPartitionOffset) {
 	} else {
 		partition.NewestOffset = o.Offset
 	}
+
+	m.publish(&Update{
+		Type:      UpdateBrokerOffset,
+		Topic:     o.Topic,
+		Partition: o.Partition,
+		BrokerOffset: &BrokerOffset{
+			OldestOffset: partition.OldestOffset,
+			NewestOffset: partition.NewestOffset,
+			Timestamp:    partition.Timestamp,
+		},
+	})
+}
+
+// checkOffsetJumps records consumer offsets committed past the newest broker offset.
+// Only offsets committed before the broker offset was fetched are checked, as a
+// consumer can legitimately be ahead of an older broker offset. The state is
+// applied concurrently, so offsets with the same timestamp are not checked.
+func (m *MemoryStore) checkOffsetJumps(o *BrokerPartitionOffset) {
+	m.state.consumerLock.Lock()
+	defer m.state.consumerLock.Unlock()
+
+	for group, topics := range m.state.consumer {
+		partitions, ok := topics[o.Topic]
+		if !ok || o.Partition < 0 || o.Partition > int32(len(partitions)-1) {
+			continue
+		}
+
+		offset := partitions[o.Partition]
+		if offset == nil || offset.Offset <= o.Offset || offset.Timestamp >= o.Timestamp || offset.jumpOffset == offset.Offset {
+			continue
+		}
+		offset.jumpOffset = offset.Offset
+
+		m.addRegression(&OffsetRegression{
+			Kind:         RegressionJump,
+			Group:        group,
+			Topic:        o.Topic,
+			Partition:    o.Partition,
+			OldOffset:    offset.previousOffset,
+			NewOffset:    offset.Offset,
+			NewestOffset: o.Offset,
+			Timestamp:    o.Timestamp,
+		})
+	}
+}
+
+func (m *MemoryStore) addRegression(r *OffsetRegression) {
+	m.state.regressionLock.Lock()
+	defer m.state.regressionLock.Unlock()
+
+	m.state.regressionID++
+	r.ID = m.state.regressionID
+
+	m.state.regressions = append(m.state.regressions, r)
+	if len(m.state.regressions) > maxRegressions {
+		m.state.regressions = m.state.regressions[len(m.state.regressions)-maxRegressions:]
+	}
 }
 
 func (m *MemoryStore) addConsumerOffset(o *ConsumerPartitionOffset) {
 	brokerOffset, partitionCount := m.getBrokerOffset(o.Topic, o.Partition)
 	if brokerOffset == -1 {
+		m.metrics.Counter("store.objects.dropped", "reason", "no_broker_offset").Inc(1)
 		return
 	}
 
@@ -271,6 +434,17 @@ func (m *MemoryStore) addConsumerOffset(o *ConsumerPartitionOffset) {
 	if offset == nil {
 		offset = &ConsumerOffset{}
 		topic[o.Partition] = offset
+	} else if o.Offset < offset.Offset && o.Timestamp >= offset.Timestamp {
+		m.addRegression(&OffsetRegression{
+			Kind:         RegressionRewind,
+			Group:        o.Group,
+			Topic:        o.Topic,
+			Partition:    o.Partition,
+			OldOffset:    offset.Offset,
+			NewOffset:    o.Offset,
+			NewestOffset: brokerOffset,
+			Timestamp:    o.Timestamp,
+		})
 	}
 
 	lag := brokerOffset - o.Offset
@@ -278,9 +452,24 @@ func (m *MemoryStore) addConsumerOffset(o *ConsumerPartitionOffset) {
 		lag = 0
 	}
 
+	if o.Offset != offset.Offset {
+		offset.previousOffset = offset.Offset
+	}
 	offset.Offset = o.Offset
 	offset.Timestamp = o.Timestamp
 	offset.Lag = lag
+
+	m.publish(&Update{
+		Type:      UpdateConsumerOffset,
+		Group:     o.Group,
+		Topic:     o.Topic,
+		Partition: o.Partition,
+		ConsumerOffset: &ConsumerOffset{
+			Offset:    offset.Offset,
+			Timestamp: offset.Timestamp,
+			Lag:       offset.Lag,
+		},
+	})
 }
 
 func (m *MemoryStore) getBrokerOffset(topic string, partition int32) (int64, int) {
@@ -329,4 +518,43 @@ func (m *MemoryStore) addMetadata(v *BrokerPartitionMetadata) {
 	partition.Replicas = v.Replicas
 	partition.Isr = v.Isr
 	partition.Timestamp = v.Timestamp
+
+	m.publish(&Update{
+		Type:      UpdateMetadata,
+		Topic:     v.Topic,
+		Partition: v.Partition,
+		Metadata: &Metadata{
+			Leader:    partition.Leader,
+			Replicas:  partition.Replicas,
+			Isr:       partition.Isr,
+			Timestamp: partition.Timestamp,
+		},
+	})
+}
+
+// publish sends an update to all subscribers without blocking.
+func (m *MemoryStore) publish(u *Update) {
+	m.subscriberLock.RLock()
+	defer m.subscriberLock.RUnlock()
+
+	for ch := range m.subscribers {
+		select {
+		case ch <- u:
+		default:
+			m.metrics.Counter("store.updates.dropped").Inc(1)
+		}
+	}
+}
+
+func (m *MemoryStore) addClusterEvent(e *ClusterEvent) {
+	m.state.eventLock.Lock()
+	defer m.state.eventLock.Unlock()
+
+	m.state.eventID++
+	e.ID = m.state.eventID
+
+	m.state.events = append(m.state.events, e)
+	if len(m.state.events) > maxEvents {
+		m.state.events = m.state.events[len(m.state.events)-maxEvents:]
+	}
 }
@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/msales/kage/metrics"
 	"github.com/msales/kage/store"
 	"github.com/stretchr/testify/assert"
 )
@@ -369,7 +370,7 @@ func TestMemoryStore_BrokerMetadata(t *testing.T) {
 		Leader:              100,
 		Replicas:            []int32{100, 101},
 		Isr:                 []int32{100, 101},
-		Timestamp:           time.Now().Unix(),
+		Timestamp:           1500000000000,
 	})
 
 	brokerMetadata := memStore.BrokerMetadata()
@@ -379,6 +380,7 @@ func TestMemoryStore_BrokerMetadata(t *testing.T) {
 	assert.Equal(t, int32(100), brokerMetadata["test"][0].Leader)
 	assert.Equal(t, []int32{100, 101}, brokerMetadata["test"][0].Replicas)
 	assert.Equal(t, []int32{100, 101}, brokerMetadata["test"][0].Isr)
+	assert.Equal(t, int64(1500000000000), brokerMetadata["test"][0].Timestamp)
 }
 
 func TestMemoryStore_BrokerMetadataMissingPartition(t *testing.T) {
@@ -494,3 +496,299 @@ func TestMemoryStore_CleanConsumerOffsetsMissingPartition(t *testing.T) {
 
 	assert.Len(t, memStore.ConsumerOffsets(), 0)
 }
+
+func TestMemoryStore_OffsetRegressionsRewind(t *testing.T) {
+	memStore, err := store.New()
+	assert.NoError(t, err)
+
+	defer memStore.Close()
+
+	memStore.SetState(&store.BrokerPartitionOffset{
+		Topic:               "test",
+		Partition:           0,
+		Oldest:              false,
+		Offset:              1000,
+		Timestamp:           100,
+		TopicPartitionCount: 1,
+	})
+	memStore.SetState(&store.ConsumerPartitionOffset{
+		Group:     "foo",
+		Topic:     "test",
+		Partition: 0,
+		Offset:    500,
+		Timestamp: 200,
+	})
+	memStore.SetState(&store.ConsumerPartitionOffset{
+		Group:     "foo",
+		Topic:     "test",
+		Partition: 0,
+		Offset:    100,
+		Timestamp: 300,
+	})
+
+	regressions := memStore.OffsetRegressions()
+
+	assert.Len(t, regressions, 1)
+	assert.Equal(t, int64(1), regressions[0].ID)
+	assert.Equal(t, store.RegressionRewind, regressions[0].Kind)
+	assert.Equal(t, "foo", regressions[0].Group)
+	assert.Equal(t, int64(500), regressions[0].OldOffset)
+	assert.Equal(t, int64(100), regressions[0].NewOffset)
+	assert.Equal(t, int64(1000), regressions[0].NewestOffset)
+}
+
+func TestMemoryStore_OffsetRegressionsIgnoresStaleOffsets(t *testing.T) {
+	memStore, err := store.New()
+	assert.NoError(t, err)
+
+	defer memStore.Close()
+
+	memStore.SetState(&store.BrokerPartitionOffset{
+		Topic:               "test",
+		Partition:           0,
+		Oldest:              false,
+		Offset:              1000,
+		Timestamp:           100,
+		TopicPartitionCount: 1,
+	})
+	memStore.SetState(&store.ConsumerPartitionOffset{
+		Group:     "foo",
+		Topic:     "test",
+		Partition: 0,
+		Offset:    500,
+		Timestamp: 300,
+	})
+	memStore.SetState(&store.ConsumerPartitionOffset{
+		Group:     "foo",
+		Topic:     "test",
+		Partition: 0,
+		Offset:    100,
+		Timestamp: 200,
+	})
+
+	assert.Len(t, memStore.OffsetRegressions(), 0)
+}
+
+func TestMemoryStore_OffsetRegressionsJump(t *testing.T) {
+	memStore, err := store.New()
+	assert.NoError(t, err)
+
+	defer memStore.Close()
+
+	memStore.SetState(&store.BrokerPartitionOffset{
+		Topic:               "test",
+		Partition:           0,
+		Oldest:              false,
+		Offset:              1000,
+		Timestamp:           100,
+		TopicPartitionCount: 1,
+	})
+	memStore.SetState(&store.ConsumerPartitionOffset{
+		Group:     "foo",
+		Topic:     "test",
+		Partition: 0,
+		Offset:    900,
+		Timestamp: 200,
+	})
+	memStore.SetState(&store.ConsumerPartitionOffset{
+		Group:     "foo",
+		Topic:     "test",
+		Partition: 0,
+		Offset:    5000,
+		Timestamp: 300,
+	})
+
+	// The consumer may be ahead of an older broker offset
+	assert.Len(t, memStore.OffsetRegressions(), 0)
+
+	memStore.SetState(&store.BrokerPartitionOffset{
+		Topic:               "test",
+		Partition:           0,
+		Oldest:              false,
+		Offset:              1100,
+		Timestamp:           400,
+		TopicPartitionCount: 1,
+	})
+	memStore.SetState(&store.BrokerPartitionOffset{
+		Topic:               "test",
+		Partition:           0,
+		Oldest:              false,
+		Offset:              1200,
+		Timestamp:           500,
+		TopicPartitionCount: 1,
+	})
+
+	regressions := memStore.OffsetRegressions()
+
+	assert.Len(t, regressions, 1)
+	assert.Equal(t, store.RegressionJump, regressions[0].Kind)
+	assert.Equal(t, int64(900), regressions[0].OldOffset)
+	assert.Equal(t, int64(5000), regressions[0].NewOffset)
+	assert.Equal(t, int64(1100), regressions[0].NewestOffset)
+}
+
+func TestMemoryStore_OffsetRegressionsJumpSameTimestamp(t *testing.T) {
+	memStore, err := store.New()
+	assert.NoError(t, err)
+
+	defer memStore.Close()
+
+	memStore.SetState(&store.BrokerPartitionOffset{
+		Topic:               "test",
+		Partition:           0,
+		Oldest:              false,
+		Offset:              1000,
+		Timestamp:           1000,
+		TopicPartitionCount: 1,
+	})
+	memStore.SetState(&store.ConsumerPartitionOffset{
+		Group:     "foo",
+		Topic:     "test",
+		Partition: 0,
+		Offset:    1100,
+		Timestamp: 2000,
+	})
+
+	// The broker offset with the same timestamp is applied after the consumer offset
+	memStore.SetState(&store.BrokerPartitionOffset{
+		Topic:               "test",
+		Partition:           0,
+		Oldest:              false,
+		Offset:              1050,
+		Timestamp:           2000,
+		TopicPartitionCount: 1,
+	})
+
+	assert.Len(t, memStore.OffsetRegressions(), 0)
+}
+
+func TestMemoryStore_ClusterEvents(t *testing.T) {
+	memStore, err := store.New()
+	assert.NoError(t, err)
+
+	defer memStore.Close()
+
+	err = memStore.SetState(&store.ClusterEvent{
+		Type:      store.EventIsrShrink,
+		Topic:     "test",
+		Partition: 0,
+		Old:       []int32{1, 2},
+		New:       []int32{1},
+		Timestamp: time.Now().Unix(),
+	})
+	assert.NoError(t, err)
+
+	events := memStore.ClusterEvents()
+
+	assert.Len(t, events, 1)
+	assert.Equal(t, int64(1), events[0].ID)
+	assert.Equal(t, store.EventIsrShrink, events[0].Type)
+	assert.Equal(t, []int32{1, 2}, events[0].Old)
+	assert.Equal(t, []int32{1}, events[0].New)
+}
+
+func TestMemoryStore_ClusterEventsBounded(t *testing.T) {
+	memStore, err := store.New()
+	assert.NoError(t, err)
+
+	defer memStore.Close()
+
+	for i := 0; i < 1010; i++ {
+		memStore.SetState(&store.ClusterEvent{Type: store.EventTopicCreated, Topic: "test"})
+	}
+
+	events := memStore.ClusterEvents()
+
+	assert.Len(t, events, 1000)
+	assert.Equal(t, int64(11), events[0].ID)
+}
+
+func TestMemoryStore_Subscribe(t *testing.T) {
+	memStore, err := store.New()
+	assert.NoError(t, err)
+
+	defer memStore.Close()
+
+	updates := memStore.Subscribe()
+
+	memStore.SetState(&store.BrokerPartitionOffset{
+		Topic:               "test",
+		Partition:           0,
+		Oldest:              false,
+		Offset:              1000,
+		Timestamp:           time.Now().Unix(),
+		TopicPartitionCount: 1,
+	})
+	memStore.SetState(&store.ConsumerPartitionOffset{
+		Group:     "foo",
+		Topic:     "test",
+		Partition: 0,
+		Offset:    500,
+		Timestamp: time.Now().Unix(),
+	})
+	memStore.SetState(&store.BrokerPartitionMetadata{
+		Topic:               "test",
+		Partition:           0,
+		TopicPartitionCount: 1,
+		Leader:              100,
+		Replicas:            []int32{100},
+		Isr:                 []int32{100},
+		Timestamp:           time.Now().Unix(),
+	})
+
+	assert.Len(t, updates, 3)
+
+	u := <-updates
+	assert.Equal(t, store.UpdateBrokerOffset, u.Type)
+	assert.Equal(t, int64(1000), u.BrokerOffset.NewestOffset)
+
+	u = <-updates
+	assert.Equal(t, store.UpdateConsumerOffset, u.Type)
+	assert.Equal(t, "foo", u.Group)
+	assert.Equal(t, int64(500), u.ConsumerOffset.Lag)
+
+	u = <-updates
+	assert.Equal(t, store.UpdateMetadata, u.Type)
+	assert.Equal(t, int32(100), u.Metadata.Leader)
+
+	memStore.Unsubscribe(updates)
+	memStore.SetState(&store.BrokerPartitionOffset{
+		Topic:               "test",
+		Partition:           0,
+		Oldest:              true,
+		Offset:              0,
+		Timestamp:           time.Now().Unix(),
+		TopicPartitionCount: 1,
+	})
+
+	assert.Len(t, updates, 0)
+}
+
+func TestMemoryStore_Metrics(t *testing.T) {
+	r := metrics.NewRegistry()
+	memStore, err := store.New(store.Metrics(r))
+	assert.NoError(t, err)
+
+	defer memStore.Close()
+
+	memStore.SetState(1)
+	memStore.SetState(&store.ConsumerPartitionOffset{
+		Group:     "foo",
+		Topic:     "test",
+		Partition: 0,
+		Offset:    10,
+		Timestamp: time.Now().Unix() * 1000,
+	})
+
+	values := map[string]float64{}
+	for _, m := range r.Snapshot() {
+		for k, v := range m.Values {
+			values[m.Name+"."+k] = v
+		}
+	}
+
+	assert.Equal(t, float64(10000), values["store.channel.capacity.value"])
+	assert.Equal(t, float64(0), values["store.channel.depth.value"])
+	assert.Equal(t, float64(1), values["store.objects.unknown.count"])
+	assert.Equal(t, float64(1), values["store.objects.dropped.count"])
+}
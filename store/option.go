@@ -0,0 +1,15 @@
+package store
+
+import (
+	"github.com/msales/kage/metrics"
+)
+
+// MemoryStoreFunc represents a function that configures the MemoryStore.
+type MemoryStoreFunc func(m *MemoryStore)
+
+// Metrics configures the internal metrics registry on the MemoryStore.
+func Metrics(r *metrics.Registry) MemoryStoreFunc {
+	return func(m *MemoryStore) {
+		m.metrics = r
+	}
+}
@@ -0,0 +1,17 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/msales/kage/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	r := metrics.NewRegistry()
+	m := &MemoryStore{}
+
+	Metrics(r)(m)
+
+	assert.Equal(t, r, m.metrics)
+}
@@ -22,6 +22,19 @@ type Metadata struct {
 	Timestamp int64
 }
 
+// BrokerStatuses represents a set of broker statuses.
+type BrokerStatuses []*BrokerStatus
+
+// BrokerStatus represents the connectivity and partition load of a broker.
+type BrokerStatus struct {
+	ID         int32
+	Connected  bool
+	Controller bool
+	Leaders    int
+	Replicas   int
+	Timestamp  int64
+}
+
 // BrokerPartitionOffset represents a brokers partition offset.
 type BrokerPartitionOffset struct {
 	Topic               string
@@ -60,4 +73,81 @@ type ConsumerOffset struct {
 	Offset    int64
 	Timestamp int64
 	Lag       int64
+
+	previousOffset int64
+	jumpOffset     int64
+}
+
+// Offset regression kinds.
+const (
+	// RegressionRewind is a consumer offset commit that moved backwards.
+	RegressionRewind = "rewind"
+	// RegressionJump is a consumer offset commit past the newest broker offset.
+	RegressionJump = "jump"
+)
+
+// OffsetRegressions represents a log of consumer offset regressions.
+type OffsetRegressions []*OffsetRegression
+
+// OffsetRegression represents a suspicious consumer offset commit.
+type OffsetRegression struct {
+	ID           int64
+	Kind         string
+	Group        string
+	Topic        string
+	Partition    int32
+	OldOffset    int64
+	NewOffset    int64
+	NewestOffset int64
+	Timestamp    int64
+}
+
+// Cluster event types.
+const (
+	EventTopicCreated    = "topic_created"
+	EventTopicDeleted    = "topic_deleted"
+	EventPartitionsAdded = "partitions_added"
+	EventLeaderChanged   = "leader_changed"
+	EventIsrShrink       = "isr_shrink"
+	EventIsrExpand       = "isr_expand"
+	EventGroupAppeared   = "group_appeared"
+	EventGroupEmptied    = "group_emptied"
+)
+
+// ClusterEvents represents a log of cluster events.
+type ClusterEvents []*ClusterEvent
+
+// ClusterEvent represents a change of the cluster topics, partitions or consumer groups.
+//
+// Old and New hold the changed values: the partition count for topic events,
+// the leader for leader changes and the replicas for ISR changes.
+type ClusterEvent struct {
+	ID        int64
+	Type      string
+	Topic     string
+	Partition int32
+	Group     string
+	Old       []int32
+	New       []int32
+	Timestamp int64
+}
+
+// Update types.
+const (
+	UpdateBrokerOffset   = "broker_offset"
+	UpdateConsumerOffset = "consumer_offset"
+	UpdateMetadata       = "metadata"
+)
+
+// Update represents a change committed to the store.
+//
+// Only the value matching the update type is set.
+type Update struct {
+	Type           string
+	Group          string
+	Topic          string
+	Partition      int32
+	BrokerOffset   *BrokerOffset
+	ConsumerOffset *ConsumerOffset
+	Metadata       *Metadata
 }
@@ -0,0 +1,17 @@
+package mocks
+
+import (
+	"github.com/msales/kage/alert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockNotifier represents a mock alert Notifier.
+type MockNotifier struct {
+	mock.Mock
+}
+
+// Notify sends the firing and resolved alerts.
+func (m *MockNotifier) Notify(alerts []*alert.Alert) error {
+	args := m.Called(alerts)
+	return args.Error(0)
+}
@@ -0,0 +1,28 @@
+package mocks
+
+import (
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockSyncProducer represents a mock Kafka producer.
+type MockSyncProducer struct {
+	mock.Mock
+}
+
+// SendMessage produces a given message.
+func (m *MockSyncProducer) SendMessage(msg *sarama.ProducerMessage) (int32, int64, error) {
+	args := m.Called(msg)
+	return int32(args.Int(0)), int64(args.Int(1)), args.Error(2)
+}
+
+// SendMessages produces a given set of messages.
+func (m *MockSyncProducer) SendMessages(msgs []*sarama.ProducerMessage) error {
+	args := m.Called(msgs)
+	return args.Error(0)
+}
+
+// Close shuts down the producer.
+func (m *MockSyncProducer) Close() error {
+	return nil
+}
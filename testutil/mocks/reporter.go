@@ -1,6 +1,7 @@
 package mocks
 
 import (
+	"github.com/msales/kage/metrics"
 	"github.com/msales/kage/store"
 	"github.com/stretchr/testify/mock"
 )
@@ -11,16 +12,37 @@ type MockReporter struct {
 }
 
 // ReportBrokerOffsets reports a snapshot of the broker offsets.
-func (m *MockReporter) ReportBrokerOffsets(v *store.BrokerOffsets) {
-	m.Called(v)
+func (m *MockReporter) ReportBrokerOffsets(v *store.BrokerOffsets) error {
+	args := m.Called(v)
+	return args.Error(0)
 }
 
 // ReportConsumerOffsets reports a snapshot of the consumer group offsets.
-func (m *MockReporter) ReportConsumerOffsets(v *store.ConsumerOffsets) {
-	m.Called(v)
+func (m *MockReporter) ReportConsumerOffsets(v *store.ConsumerOffsets) error {
+	args := m.Called(v)
+	return args.Error(0)
+}
+
+// ReportOffsetRegressions reports newly detected consumer offset regressions.
+func (m *MockReporter) ReportOffsetRegressions(v *store.OffsetRegressions) error {
+	args := m.Called(v)
+	return args.Error(0)
 }
 
 // ReportBrokerMetadata reports a snapshot of the broker metadata.
-func (m *MockReporter) ReportBrokerMetadata(v *store.BrokerMetadata) {
-	m.Called(v)
+func (m *MockReporter) ReportBrokerMetadata(v *store.BrokerMetadata) error {
+	args := m.Called(v)
+	return args.Error(0)
+}
+
+// ReportBrokerStatus reports the status of the brokers.
+func (m *MockReporter) ReportBrokerStatus(v *store.BrokerStatuses) error {
+	args := m.Called(v)
+	return args.Error(0)
+}
+
+// ReportMetrics reports a snapshot of the internal metrics.
+func (m *MockReporter) ReportMetrics(v *metrics.Snapshot) error {
+	args := m.Called(v)
+	return args.Error(0)
 }
@@ -34,12 +34,35 @@ func (m *MockStore) BrokerMetadata() store.BrokerMetadata {
 	return args.Get(0).(store.BrokerMetadata)
 }
 
+// OffsetRegressions returns a snapshot of the consumer offset regression log.
+func (m *MockStore) OffsetRegressions() store.OffsetRegressions {
+	args := m.Called()
+	return args.Get(0).(store.OffsetRegressions)
+}
+
+// ClusterEvents returns a snapshot of the cluster event log.
+func (m *MockStore) ClusterEvents() store.ClusterEvents {
+	args := m.Called()
+	return args.Get(0).(store.ClusterEvents)
+}
+
 // Channel get the offset channel.
 func (m *MockStore) Channel() chan interface{} {
 	args := m.Called()
 	return args.Get(0).(chan interface{})
 }
 
+// Subscribe returns a channel receiving the changes committed to the store.
+func (m *MockStore) Subscribe() chan *store.Update {
+	args := m.Called()
+	return args.Get(0).(chan *store.Update)
+}
+
+// Unsubscribe stops sending updates to the given channel.
+func (m *MockStore) Unsubscribe(ch chan *store.Update) {
+	m.Called(ch)
+}
+
 // Close gracefully stops the Store.
 func (m *MockStore) Close() {
 	m.Called()
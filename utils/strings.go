@@ -1,6 +1,9 @@
 package utils
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+)
 
 // SplitMap splits a slice of strings into a map of strings using
 // the given separator.
@@ -19,3 +22,24 @@ func SplitMap(s []string, sep string) map[string]string {
 	}
 	return m
 }
+
+// SplitMapSlice splits a slice of strings into a map of string slices using
+// the given separator, collecting the values of repeated keys. An error is
+// returned for a string without the separator.
+func SplitMapSlice(s []string, sep string) (map[string][]string, error) {
+	if len(s) == 0 || sep == "" {
+		return nil, nil
+	}
+
+	m := make(map[string][]string)
+	for _, str := range s {
+		parts := strings.SplitN(str, sep, 2)
+		if len(parts) < 2 {
+			return nil, fmt.Errorf("missing \"%s\" in \"%s\"", sep, str)
+		}
+
+		m[parts[0]] = append(m[parts[0]], parts[1])
+	}
+
+	return m, nil
+}
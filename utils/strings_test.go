@@ -45,3 +45,44 @@ func TestSplitMap(t *testing.T) {
 		assert.Equal(t, test.expect, out)
 	}
 }
+
+func TestSplitMapSlice(t *testing.T) {
+	tests := []struct {
+		in     []string
+		sep    string
+		expect map[string][]string
+		err    bool
+	}{
+		{
+			in:     []string{"foo=bar", "foo=baz", "bar=a=b"},
+			sep:    "=",
+			expect: map[string][]string{"foo": {"bar", "baz"}, "bar": {"a=b"}},
+		},
+		{
+			in:  []string{"foo", "bar=baz"},
+			sep: "=",
+			err: true,
+		},
+		{
+			in:     nil,
+			sep:    "=",
+			expect: nil,
+		},
+		{
+			in:     []string{"foo=bar"},
+			sep:    "",
+			expect: nil,
+		},
+	}
+
+	for _, test := range tests {
+		out, err := utils.SplitMapSlice(test.in, test.sep)
+		if test.err {
+			assert.Error(t, err)
+			continue
+		}
+
+		assert.NoError(t, err)
+		assert.Equal(t, test.expect, out)
+	}
+}
@@ -0,0 +1,12 @@
+package utils
+
+import "time"
+
+// Millis converts the time to Unix time in milliseconds, or 0 when it is unset.
+func Millis(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+
+	return t.UnixNano() / int64(time.Millisecond)
+}
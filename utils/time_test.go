@@ -0,0 +1,14 @@
+package utils_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/msales/kage/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMillis(t *testing.T) {
+	assert.Equal(t, int64(1500000000123), utils.Millis(time.Unix(1500000000, 123*int64(time.Millisecond))))
+	assert.Equal(t, int64(0), utils.Millis(time.Time{}))
+}